@@ -0,0 +1,33 @@
+package byteslice
+
+import (
+	"encoding/ascii85"
+	"fmt"
+)
+
+// Ascii85Encoder is a B64Encoder that encodes `[]byte` into an ascii85
+// string using "encoding/ascii85", as used in PostScript and PDF.
+// Groups of four zero bytes are encoded as a single 'z' character.
+var Ascii85Encoder B64Encoder = ascii85Codec{}
+
+// Ascii85Decoder is a B64Decoder that decodes ascii85 strings using
+// "encoding/ascii85". Whitespace in the input is ignored.
+var Ascii85Decoder B64Decoder = ascii85Codec{}
+
+type ascii85Codec struct{}
+
+func (ascii85Codec) EncodeToString(src []byte) string {
+	dst := make([]byte, ascii85.MaxEncodedLen(len(src)))
+	n := ascii85.Encode(dst, src)
+	return string(dst[:n])
+}
+
+func (ascii85Codec) DecodeString(src string) ([]byte, error) {
+	// Each character decodes to at most 4 bytes (the 'z' shortcut)
+	dst := make([]byte, 4*len(src))
+	n, _, err := ascii85.Decode(dst, []byte(src), true)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to decode ascii85 string: %w`, err)
+	}
+	return dst[:n], nil
+}
@@ -0,0 +1,27 @@
+package byteslice
+
+import (
+	"encoding/base32"
+)
+
+// Encoders and decoders for the base32 encodings defined in RFC 4648.
+// Any `*base32.Encoding` object satisfies both B64Encoder and B64Decoder,
+// so these are merely provided for convenience.
+var (
+	// Base32StdEncoder encodes using the standard base32 encoding, with padding
+	Base32StdEncoder B64Encoder = base32.StdEncoding
+	// Base32StdDecoder decodes using the standard base32 encoding, with padding
+	Base32StdDecoder B64Decoder = base32.StdEncoding
+	// Base32HexEncoder encodes using the "Extended Hex Alphabet" base32 encoding, with padding
+	Base32HexEncoder B64Encoder = base32.HexEncoding
+	// Base32HexDecoder decodes using the "Extended Hex Alphabet" base32 encoding, with padding
+	Base32HexDecoder B64Decoder = base32.HexEncoding
+	// Base32RawStdEncoder encodes using the standard base32 encoding, without padding
+	Base32RawStdEncoder B64Encoder = base32.StdEncoding.WithPadding(base32.NoPadding)
+	// Base32RawStdDecoder decodes using the standard base32 encoding, without padding
+	Base32RawStdDecoder B64Decoder = base32.StdEncoding.WithPadding(base32.NoPadding)
+	// Base32RawHexEncoder encodes using the "Extended Hex Alphabet" base32 encoding, without padding
+	Base32RawHexEncoder B64Encoder = base32.HexEncoding.WithPadding(base32.NoPadding)
+	// Base32RawHexDecoder decodes using the "Extended Hex Alphabet" base32 encoding, without padding
+	Base32RawHexDecoder B64Decoder = base32.HexEncoding.WithPadding(base32.NoPadding)
+)
@@ -0,0 +1,94 @@
+package byteslice
+
+import (
+	"fmt"
+)
+
+// Base58Encoder is a B64Encoder that encodes `[]byte` into a base58
+// string, using the alphabet used by Bitcoin and IPFS. Leading zero
+// bytes are encoded as leading '1' characters.
+var Base58Encoder B64Encoder = base58Codec{}
+
+// Base58Decoder is a B64Decoder that decodes base58 strings, using the
+// alphabet used by Bitcoin and IPFS.
+var Base58Decoder B64Decoder = base58Codec{}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58DecodeMap [256]byte
+
+func init() {
+	for i := range base58DecodeMap {
+		base58DecodeMap[i] = 0xff
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		base58DecodeMap[base58Alphabet[i]] = byte(i)
+	}
+}
+
+type base58Codec struct{}
+
+func (base58Codec) EncodeToString(src []byte) string {
+	var zeros int
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+
+	// log(256) / log(58) is approximately 1.37
+	size := (len(src)-zeros)*138/100 + 1
+	buf := make([]byte, size)
+
+	var length int
+	for _, c := range src[zeros:] {
+		carry := int(c)
+		var i int
+		for j := size - 1; (carry != 0 || i < length) && j >= 0; j-- {
+			carry += 256 * int(buf[j])
+			buf[j] = byte(carry % 58)
+			carry /= 58
+			i++
+		}
+		length = i
+	}
+
+	out := make([]byte, zeros+length)
+	for i := 0; i < zeros; i++ {
+		out[i] = base58Alphabet[0]
+	}
+	for i, v := range buf[size-length:] {
+		out[zeros+i] = base58Alphabet[v]
+	}
+	return string(out)
+}
+
+func (base58Codec) DecodeString(src string) ([]byte, error) {
+	var zeros int
+	for zeros < len(src) && src[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	// log(58) / log(256) is approximately 0.733
+	size := (len(src)-zeros)*733/1000 + 1
+	buf := make([]byte, size)
+
+	var length int
+	for pos := zeros; pos < len(src); pos++ {
+		carry := int(base58DecodeMap[src[pos]])
+		if carry == 0xff {
+			return nil, fmt.Errorf(`invalid base58 character %q at position %d`, src[pos], pos)
+		}
+
+		var i int
+		for j := size - 1; (carry != 0 || i < length) && j >= 0; j-- {
+			carry += 58 * int(buf[j])
+			buf[j] = byte(carry % 256)
+			carry /= 256
+			i++
+		}
+		length = i
+	}
+
+	out := make([]byte, zeros+length)
+	copy(out[zeros:], buf[size-length:])
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package byteslice_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase58(t *testing.T) {
+	// Test vectors from Bitcoin Core's base58_encode_decode.json
+	testcases := []struct {
+		Hex     string
+		Encoded string
+	}{
+		{Hex: "", Encoded: ""},
+		{Hex: "61", Encoded: "2g"},
+		{Hex: "626262", Encoded: "a3gV"},
+		{Hex: "636363", Encoded: "aPEr"},
+		{Hex: "73696d706c792061206c6f6e6720737472696e67", Encoded: "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{Hex: "00eb15231dfceb60925886b67d065299925915aeb172c06647", Encoded: "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{Hex: "516b6fcd0f", Encoded: "ABnLTmg"},
+		{Hex: "bf4f89001e670274dd", Encoded: "3SEo3LWLoPntC"},
+		{Hex: "572e4794", Encoded: "3EFU7m"},
+		{Hex: "ecac89cad93923c02321", Encoded: "EJDM8drfXA6uyA"},
+		{Hex: "10c8511e", Encoded: "Rt5zm"},
+		{Hex: "00000000000000000000", Encoded: "1111111111"},
+		{Hex: "000111d38e5fc9071ffcd20b4a763cc9ae4f252bb4e48fd66a835e252ada93ff480d6dd43dc62a641155a5", Encoded: "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Encoded, func(t *testing.T) {
+			raw, err := hex.DecodeString(tc.Hex)
+			require.NoError(t, err, `hex.DecodeString should succeed`)
+
+			require.Equal(t, tc.Encoded, byteslice.Base58Encoder.EncodeToString(raw))
+
+			decoded, err := byteslice.Base58Decoder.DecodeString(tc.Encoded)
+			require.NoError(t, err, `DecodeString should succeed`)
+			require.Equal(t, raw, decoded)
+		})
+	}
+	t.Run("invalid", func(t *testing.T) {
+		_, err := byteslice.Base58Decoder.DecodeString(`3EFU0m`)
+		require.Error(t, err, `DecodeString should fail for '0'`)
+	})
+	t.Run("Buffer", func(t *testing.T) {
+		v := byteslice.New([]byte{0x00, 0x00, 0x61}, byteslice.WithB64Encoder(byteslice.Base58Encoder))
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"112g"`, string(buf))
+
+		rt := byteslice.New(nil, byteslice.WithB64Decoder(byteslice.Base58Decoder))
+		require.NoError(t, json.Unmarshal(buf, rt), `json.Unmarshal should succeed`)
+		require.Equal(t, v.Bytes(), rt.Bytes())
+	})
+}
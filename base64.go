@@ -1,7 +1,10 @@
 package byteslice
 
 import (
+	"bytes"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 )
@@ -22,6 +25,25 @@ type B64Encoder interface {
 	EncodeToString([]byte) string
 }
 
+// B64Codec is the interface for objects that can both encode and decode,
+// and therefore act as both B64Encoder and B64Decoder.
+//
+// Any `*base64.Encoding` or `*base32.Encoding` object satisfies
+// this interface.
+type B64Codec interface {
+	B64Encoder
+	B64Decoder
+}
+
+// AppendEncoder is an optional interface that B64Encoder objects may
+// implement to append the encoded form of `src` to `dst`, without
+// allocating an intermediate string.
+//
+// `*base64.Encoding` objects implement this interface as of Go 1.22.
+type AppendEncoder interface {
+	AppendEncode(dst, src []byte) []byte
+}
+
 var globalMu sync.RWMutex
 var globalDecoder B64Decoder
 var globalEncoder B64Encoder
@@ -47,7 +69,8 @@ func SetGlobalB64Encoder(enc B64Encoder) {
 // use its own decoder if set individually.
 //
 // The default decoder uses heuristics to determine which of the `"encoding/base64".Encoding`
-// objects should be used.
+// objects should be used. Surrounding ASCII whitespace is ignored, and an empty
+// string is decoded to an empty `[]byte`.
 //
 //   - If the incoming payload does NOT contain a '=' at the end of the string, it is considered to be a "raw" base64 encoding (i.e. no padding)
 //   - If the incoming payload does NOT contain either '+' or '/', it is considered to be a "url" encoding
@@ -75,12 +98,291 @@ func GlobalB64Encoder() B64Encoder {
 	return globalEncoder
 }
 
+func checkPadding(src string, padding string) error {
+	trimmed := strings.TrimRight(src, padding)
+	if n := len(src) - len(trimmed); n > 2 {
+		return withKind(ErrInvalidPadding, fmt.Errorf(`invalid base64 padding: expected at most 2 padding characters, got %d`, n))
+	}
+	if i := strings.Index(trimmed, padding); i >= 0 {
+		return withKind(ErrInvalidPadding, fmt.Errorf(`invalid base64 padding: unexpected padding character at position %d`, i))
+	}
+	return nil
+}
+
+// fixPadding replaces the trailing padding of `src`, if any, with the
+// amount of padding that its length requires
+func fixPadding(src string, padding string) string {
+	trimmed := strings.TrimRight(src, padding)
+	if len(trimmed) == len(src) {
+		return src
+	}
+	if n := len(trimmed) % 4; n > 1 {
+		return trimmed + strings.Repeat(padding, 4-n)
+	}
+	return trimmed
+}
+
+// decodedLen returns the number of bytes that `src` decodes to, if it
+// can be computed from the length of `src` without decoding it.
+func decodedLen(dec B64Decoder, src string) (int, bool) {
+	switch dec := dec.(type) {
+	case *HeuristicDecoder:
+		src = strings.Trim(src, asciiSpace)
+		if padding := dec.paddingChar(); padding != base64.NoPadding {
+			src = strings.TrimRight(src, string(padding))
+		}
+		return len(src) * 6 / 8, true
+	case *base64.Encoding:
+		src = strings.Trim(src, asciiSpace)
+		if padding := encodingPadding(dec); padding != base64.NoPadding {
+			src = strings.TrimRight(src, string(padding))
+		}
+		return len(src) * 6 / 8, true
+	case whitespaceStripper:
+		return decodedLen(dec.dec, stripWhitespace(src))
+	default:
+		return 0, false
+	}
+}
+
+// StripWhitespace wraps `dec` in a B64Decoder that removes all spaces,
+// tabs, carriage returns and newlines from the input before decoding it
+// using `dec`. Use this to decode PEM or MIME style base64 payloads that
+// are wrapped at a fixed column width.
+//
+// `dec` may be any B64Decoder, including the default heuristic decoder
+// and `*base64.Encoding` objects.
+func StripWhitespace(dec B64Decoder) B64Decoder {
+	return whitespaceStripper{dec: dec}
+}
+
+type whitespaceStripper struct {
+	dec B64Decoder
+}
+
+func (d whitespaceStripper) DecodeString(src string) ([]byte, error) {
+	buf, _, err := d.decodeStringDetect(src)
+	return buf, err
+}
+
+func (d whitespaceStripper) decodeStringDetect(src string) ([]byte, *base64.Encoding, error) {
+	return decodeString(d.dec, stripWhitespace(src))
+}
+
+func isWhitespace(c rune) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n':
+		return true
+	default:
+		return false
+	}
+}
+
+func stripWhitespace(src string) string {
+	if strings.IndexFunc(src, isWhitespace) < 0 {
+		return src
+	}
+	return strings.Map(func(c rune) rune {
+		if isWhitespace(c) {
+			return -1
+		}
+		return c
+	}, src)
+}
+
+// NewStrictDecoder creates a B64Decoder that always decodes using `enc`,
+// bypassing any heuristics. The decoder operates in strict mode (see
+// `"encoding/base64".Encoding.Strict`), so that trailing padding bits
+// must be zero.
+func NewStrictDecoder(enc *base64.Encoding) B64Decoder {
+	return enc.Strict()
+}
+
+// strictB64Decoder validates its input character by character before
+// decoding it using the strict variant of `enc`. If `enc` is nil, the
+// encoding is chosen using the heuristics of HeuristicDecoder, without
+// trimming any whitespace.
+type strictB64Decoder struct {
+	enc *base64.Encoding
+}
+
+func (d strictB64Decoder) DecodeString(src string) ([]byte, error) {
+	buf, _, err := d.decodeStringDetect(src)
+	return buf, err
+}
+
+func (d strictB64Decoder) decodeStringDetect(src string) ([]byte, *base64.Encoding, error) {
+	enc := d.enc
+	if enc == nil {
+		for i := 0; i < len(src); i++ {
+			if !isBase64Char(src[i]) {
+				return nil, nil, fmt.Errorf(`invalid base64 string: illegal character %q at position %d`, src[i], i)
+			}
+		}
+
+		isRaw := !strings.HasSuffix(src, "=")
+		isURL := !strings.ContainsAny(src, "+/")
+		enc = chooseEncoding(isRaw, isURL, base64.StdEncoding, base64.URLEncoding)
+	}
+
+	// "encoding/base64" silently skips newlines, so reject them explicitly
+	if i := strings.IndexAny(src, "\r\n"); i >= 0 {
+		return nil, nil, fmt.Errorf(`invalid base64 string: illegal character %q at position %d`, src[i], i)
+	}
+
+	buf, err := enc.Strict().DecodeString(src)
+	if err != nil {
+		var cerr base64.CorruptInputError
+		if errors.As(err, &cerr) {
+			if int(cerr) < len(src) {
+				return nil, nil, fmt.Errorf(`invalid base64 string: unexpected character %q at position %d: %w`, src[cerr], int(cerr), err)
+			}
+			return nil, nil, fmt.Errorf(`invalid base64 string: invalid length %d: %w`, len(src), err)
+		}
+		return nil, nil, err
+	}
+	return buf, enc, nil
+}
+
+// isBase64Char returns true if `c` belongs to either the standard or the
+// URL-safe base64 alphabet, or is the standard padding character
+func isBase64Char(c byte) bool {
+	switch {
+	case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		return true
+	case c == '+', c == '/', c == '-', c == '_', c == '=':
+		return true
+	default:
+		return false
+	}
+}
+
+// NewMultiSegmentDecoder creates a B64Decoder that accepts several
+// padded base64 blocks concatenated together, such as "QQ==Qg==",
+// which `enc` alone would reject. The input is split after each run
+// of padding characters, each segment is decoded using `enc`, and the
+// results are concatenated.
+//
+// This is not standard base64, and must be opted into explicitly.
+// If `enc` does not use padding, the decoder behaves exactly like `enc`.
+func NewMultiSegmentDecoder(enc *base64.Encoding) B64Decoder {
+	return multiSegmentDecoder{enc: enc, padding: encodingPadding(enc)}
+}
+
+// encodingPadding returns the padding character used by `enc`, or
+// `base64.NoPadding` if it does not use padding.
+func encodingPadding(enc *base64.Encoding) rune {
+	// *base64.Encoding does not expose its padding character, so find
+	// it by encoding a single byte, which always requires padding
+	if s := enc.EncodeToString([]byte{0}); len(s) == 4 {
+		return rune(s[3])
+	}
+	return base64.NoPadding
+}
+
+const stdAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+const urlAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// alphabetProbe is encoded to the 64 characters of the alphabet, in order
+var alphabetProbe, _ = base64.StdEncoding.DecodeString(stdAlphabet)
+
+// encodingAlphabet reports whether `enc` uses the standard (isURL is false)
+// or the URL-safe (isURL is true) alphabet. Variants such as those returned
+// by Strict() or WithPadding() are distinct objects, so they are identified
+// by encoding alphabetProbe. ok is false for any other alphabet.
+func encodingAlphabet(enc *base64.Encoding) (isURL bool, ok bool) {
+	var alphabet [64]byte
+	enc.Encode(alphabet[:], alphabetProbe)
+
+	switch string(alphabet[:]) {
+	case stdAlphabet:
+		return false, true
+	case urlAlphabet:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+type multiSegmentDecoder struct {
+	enc     *base64.Encoding
+	padding rune
+}
+
+func (d multiSegmentDecoder) DecodeString(src string) ([]byte, error) {
+	if d.padding < 0 {
+		return d.enc.DecodeString(src)
+	}
+
+	out := []byte{}
+	for i := 0; len(src) > 0; i++ {
+		end := strings.IndexByte(src, byte(d.padding))
+		if end < 0 {
+			end = len(src)
+		}
+		for end < len(src) && src[end] == byte(d.padding) {
+			end++
+		}
+
+		buf, err := d.enc.DecodeString(src[:end])
+		if err != nil {
+			return nil, fmt.Errorf(`failed to decode base64 segment %d: %w`, i, err)
+		}
+		out = append(out, buf...)
+		src = src[end:]
+	}
+	return out, nil
+}
+
+// NewCustomB64 creates a B64Encoder and B64Decoder pair for a base64
+// encoding using a non-standard alphabet. The alphabet must be a string
+// of 64 unique bytes. `padding` specifies the padding character,
+// or `base64.NoPadding` to disable padding.
+//
+// An error is returned, instead of panicking as `base64.NewEncoding`
+// does, if either the alphabet or the padding character is invalid.
+func NewCustomB64(alphabet string, padding rune) (B64Encoder, B64Decoder, error) {
+	if len(alphabet) != 64 {
+		return nil, nil, fmt.Errorf(`invalid base64 alphabet: expected 64 bytes, got %d`, len(alphabet))
+	}
+
+	var seen [256]bool
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c == '\n' || c == '\r' {
+			return nil, nil, fmt.Errorf(`invalid base64 alphabet: contains newline character`)
+		}
+		if seen[c] {
+			return nil, nil, fmt.Errorf(`invalid base64 alphabet: duplicate symbol %q`, c)
+		}
+		seen[c] = true
+	}
+
+	if padding != base64.NoPadding {
+		if padding == '\n' || padding == '\r' || padding < 0 || padding > 0xff {
+			return nil, nil, fmt.Errorf(`invalid base64 padding character %q`, padding)
+		}
+		if seen[byte(padding)] {
+			return nil, nil, fmt.Errorf(`invalid base64 padding character %q: contained in alphabet`, padding)
+		}
+	}
+
+	enc := base64.NewEncoding(alphabet).WithPadding(padding)
+	return enc, enc, nil
+}
+
 // B64DecoderFunc is an instance of B64Decoder that is based on
 // a function.
 type B64DecoderFunc func(string) ([]byte, error)
 
-// DecodeString implements the B64Decoder interface
+var _ B64Decoder = B64DecoderFunc(nil)
+
+// DecodeString implements the B64Decoder interface. If `f` is nil,
+// an error is returned.
 func (f B64DecoderFunc) DecodeString(s string) ([]byte, error) {
+	if f == nil {
+		return nil, fmt.Errorf(`nil byteslice.B64DecoderFunc`)
+	}
 	return f(s)
 }
 
@@ -88,31 +390,220 @@ func (f B64DecoderFunc) DecodeString(s string) ([]byte, error) {
 // a function.
 type B64EncoderFunc func([]byte) string
 
-// EncodeString implements the B64Encoder interface
+var _ B64Encoder = B64EncoderFunc(nil)
+
+// EncodeToString implements the B64Encoder interface. As the interface
+// does not allow returning an error, EncodeToString panics if `f` is nil.
 func (f B64EncoderFunc) EncodeToString(data []byte) string {
+	if f == nil {
+		panic(`byteslice.B64EncoderFunc: nil function`)
+	}
 	return f(data)
 }
 
-func defaultDecodeString(src string) ([]byte, error) {
-	var enc *base64.Encoding
+// detectingDecoder is implemented by decoders that can report which
+// `*base64.Encoding` object was used to decode a string
+type detectingDecoder interface {
+	decodeStringDetect(string) ([]byte, *base64.Encoding, error)
+}
+
+// decodeString decodes `src` using `dec`, and reports the `*base64.Encoding`
+// object that was used, if it can be determined.
+func decodeString(dec B64Decoder, src string) ([]byte, *base64.Encoding, error) {
+	switch dec := dec.(type) {
+	case detectingDecoder:
+		return dec.decodeStringDetect(src)
+	case *base64.Encoding:
+		buf, err := dec.DecodeString(src)
+		return buf, dec, err
+	default:
+		buf, err := dec.DecodeString(src)
+		return buf, nil, err
+	}
+}
+
+// HeuristicB64Decoder is the default global B64Decoder. It can be used
+// to restore the default behavior after the global decoder has been
+// changed via SetGlobalB64Decoder().
+var HeuristicB64Decoder B64Decoder = NewHeuristicDecoder()
+
+// HeuristicDecoder is a B64Decoder that uses heuristics to determine
+// which of the `"encoding/base64".Encoding` objects should be used to
+// decode a given string. See GlobalB64Decoder() for the heuristics used.
+//
+// Note that the heuristics are inherently ambiguous: the standard and
+// URL-safe alphabets only differ in two characters, so a string that
+// contains none of them is accepted as either. For example, a string that
+// was meant to be in the standard encoding is silently accepted even if
+// it contains URL-safe characters. If the expected encoding is known,
+// use NewStrictDecoder() instead.
+//
+// By default the decoder does not inspect the padding itself, and leaves
+// any error reporting to the selected `*base64.Encoding` object. Use
+// StrictPadding() to validate the padding before decoding, or
+// LenientPadding() to correct it instead.
+//
+// The padding character is assumed to be '=', unless a different one is
+// specified using SetPadding().
+type HeuristicDecoder struct {
+	strictPadding  bool
+	lenientPadding bool
+	padding        rune // 0 means '='
+	std            *base64.Encoding
+	url            *base64.Encoding
+}
+
+const asciiSpace = " \t\r\n\v\f"
+
+// NewHeuristicDecoder creates a new HeuristicDecoder object.
+func NewHeuristicDecoder() *HeuristicDecoder {
+	return &HeuristicDecoder{}
+}
+
+// StrictPadding specifies if the padding should be validated before
+// decoding. When enabled, inputs that contain more than two trailing
+// '=' characters, or that contain '=' anywhere other than at the end,
+// are rejected with a descriptive error.
+//
+// StrictPadding and LenientPadding are mutually exclusive: enabling
+// one disables the other.
+func (d *HeuristicDecoder) StrictPadding(v bool) *HeuristicDecoder {
+	d.strictPadding = v
+	if v {
+		d.lenientPadding = false
+	}
+	return d
+}
+
+// LenientPadding specifies if incorrect trailing padding should be
+// corrected before decoding. When enabled, trailing padding characters
+// are added or removed so that the input has exactly the amount of
+// padding its length requires. Inputs without any padding are left
+// as is, and decoded as "raw" base64.
+//
+// This tolerates sloppy encoders that are common in JWT tooling, which
+// sometimes add stray padding to URL-safe base64 that should have none.
+//
+// StrictPadding and LenientPadding are mutually exclusive: enabling
+// one disables the other.
+func (d *HeuristicDecoder) LenientPadding(v bool) *HeuristicDecoder {
+	d.lenientPadding = v
+	if v {
+		d.strictPadding = false
+	}
+	return d
+}
+
+// SetPadding specifies the padding character used by the encoded input,
+// for variants of base64 that use a character other than '='. The padding
+// character is used both to determine if the input is padded or "raw",
+// and to decode the input.
+//
+// `r` must be an ASCII character that is not part of either the standard
+// or the URL-safe alphabet, or `base64.NoPadding`, in which case all input
+// is treated as raw. SetPadding panics if `r` is invalid, in the same way
+// `"encoding/base64".Encoding.WithPadding` does.
+func (d *HeuristicDecoder) SetPadding(r rune) *HeuristicDecoder {
+	if r == base64.StdPadding {
+		d.padding, d.std, d.url = 0, nil, nil
+		return d
+	}
+	if r > 0x7f {
+		panic(`byteslice.HeuristicDecoder: invalid padding`)
+	}
+	d.std = base64.StdEncoding.WithPadding(r)
+	d.url = base64.URLEncoding.WithPadding(r)
+	d.padding = r
+	return d
+}
+
+func (d *HeuristicDecoder) paddingChar() rune {
+	if d.padding == 0 {
+		return base64.StdPadding
+	}
+	return d.padding
+}
+
+// DecodeString implements the B64Decoder interface
+func (d *HeuristicDecoder) DecodeString(src string) ([]byte, error) {
+	buf, _, err := d.decodeStringDetect(src)
+	return buf, err
+}
+
+func (d *HeuristicDecoder) decodeStringDetect(src string) ([]byte, *base64.Encoding, error) {
+	src = strings.Trim(src, asciiSpace)
+	if src == "" {
+		return []byte{}, nil, nil
+	}
+
+	var isRaw = true
+	if r := d.paddingChar(); r != base64.NoPadding {
+		padding := string(r)
+		if strings.Trim(src, padding) == "" {
+			return nil, nil, withKind(ErrInvalidPadding, fmt.Errorf(`invalid base64 string: input consists only of padding`))
+		}
+		if d.strictPadding {
+			if err := checkPadding(src, padding); err != nil {
+				return nil, nil, err
+			}
+		}
+		if d.lenientPadding {
+			src = fixPadding(src, padding)
+		}
+		isRaw = !strings.HasSuffix(src, padding)
+	}
+
+	std, url := base64.StdEncoding, base64.URLEncoding
+	if d.std != nil {
+		std, url = d.std, d.url
+	}
 
-	var isRaw = !strings.HasSuffix(src, "=")
 	var isURL = !strings.ContainsAny(src, "+/")
+	enc := chooseEncoding(isRaw, isURL, std, url)
+
+	buf, err := enc.DecodeString(src)
+	return buf, enc, err
+}
+
+// decodeBytesDetect is the `[]byte` counterpart of decodeStringDetect,
+// used to avoid allocating a string. ok is false if the decoder is
+// configured in a way that is only supported by decodeStringDetect,
+// or if the input is empty or consists only of padding.
+func (d *HeuristicDecoder) decodeBytesDetect(src []byte) (buf []byte, enc *base64.Encoding, ok bool, err error) {
+	if d.strictPadding || d.lenientPadding || d.padding != 0 {
+		return nil, nil, false, nil
+	}
+
+	src = bytes.Trim(src, asciiSpace)
+	if len(bytes.TrimRight(src, "=")) == 0 {
+		return nil, nil, false, nil
+	}
+
+	isRaw := !bytes.HasSuffix(src, []byte("="))
+	isURL := !bytes.ContainsAny(src, "+/")
+	enc = chooseEncoding(isRaw, isURL, base64.StdEncoding, base64.URLEncoding)
+
+	buf = make([]byte, enc.DecodedLen(len(src)))
+	n, err := enc.Decode(buf, src)
+	return buf[:n], enc, true, err
+}
+
+// chooseEncoding returns the variant of `std` or `url` as determined
+// by the heuristics of HeuristicDecoder
+func chooseEncoding(isRaw, isURL bool, std, url *base64.Encoding) *base64.Encoding {
 	switch {
 	case isRaw && isURL:
-		enc = base64.RawURLEncoding
+		return base64.RawURLEncoding
 	case isURL:
-		enc = base64.URLEncoding
+		return url
 	case isRaw:
-		enc = base64.RawStdEncoding
+		return base64.RawStdEncoding
 	default:
-		enc = base64.StdEncoding
+		return std
 	}
-
-	return enc.DecodeString(src)
 }
 
 func init() {
-	SetGlobalB64Decoder(B64DecoderFunc(defaultDecodeString))
+	SetGlobalB64Decoder(HeuristicB64Decoder)
 	SetGlobalB64Encoder(base64.StdEncoding)
 }
@@ -12,8 +12,20 @@
 package byteslice
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Buffer represents a byte slice. Its only purpose is to act
@@ -23,27 +35,96 @@ import (
 // It is safe to use the zero value of the `Buffer` object,
 // but the object is not explicitly synchronized. The user
 // must make sure to apply any synchronization if need be.
+// Conversely, no locking is performed internally, so buffers that are
+// owned by a single goroutine incur no synchronization overhead.
 //
 // You should not copy a `Buffer` object by reference
 type Buffer struct {
-	data    []byte
-	decoder B64Decoder
-	encoder B64Encoder
+	data      []byte
+	decoder   B64Decoder
+	encoder   B64Encoder
+	nilAsNull bool
+	jsonArray bool
+	roundTrip bool
+	detected  *base64.Encoding
+
+	selfDescribing bool
+
+	maxDecodeLen int
+	validator    func([]byte) error
 }
 
 // New creates a new buffer. Using the data provided to call SetBytes().
 // You may pass `nil` to the argument to create an uninitialized `Buffer` object.
 //
+// Options such as `WithB64Encoder()` and `WithB64Decoder()` may be
+// passed to configure the new object.
+//
 // If you do not need explicit initialization, it is safe to use the
 // zero value of the `Buffer` object.
-func New(data []byte) *Buffer {
+func New(data []byte, options ...Option) *Buffer {
 	b := &Buffer{}
+	rawCopy := true
+	for _, option := range options {
+		switch option.Ident() {
+		case identB64Encoder{}:
+			enc, _ := option.Value().(B64Encoder)
+			b.SetB64Encoder(enc)
+		case identB64Decoder{}:
+			dec, _ := option.Value().(B64Decoder)
+			b.SetB64Decoder(dec)
+		case identRawCopy{}:
+			rawCopy = option.Value().(bool)
+		}
+	}
+
 	if data != nil {
-		b.SetBytes(data)
+		if rawCopy {
+			b.SetBytes(data)
+		} else {
+			b.data = data
+		}
 	}
 	return b
 }
 
+// Concat creates a new buffer containing the concatenation of the
+// contents of `bufs`. The total length is computed up front, so that
+// the data is copied exactly once. nil `Buffer` objects are skipped.
+//
+// The returned object uses the global encoder and decoder; per-instance
+// configuration of `bufs` is not carried over.
+func Concat(bufs ...*Buffer) *Buffer {
+	var l int
+	for _, b := range bufs {
+		l += b.Len()
+	}
+
+	data := make([]byte, 0, l)
+	for _, b := range bufs {
+		data = append(data, b.Bytes()...)
+	}
+	return &Buffer{data: data}
+}
+
+// NewFromString creates a new buffer from a base64 encoded string.
+//
+// The string is decoded using the global decoder, unless a B64Decoder
+// is explicitly passed, in which case it is used for this call only.
+// The decoder is not associated with the returned `Buffer` object.
+func NewFromString(s string, decoders ...B64Decoder) (*Buffer, error) {
+	dec := GlobalB64Decoder()
+	if len(decoders) > 0 {
+		dec = decoders[0]
+	}
+
+	buf, err := dec.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to decode string for byteslice.Buffer: %w`, err)
+	}
+	return &Buffer{data: buf}, nil
+}
+
 // B64Decoder returns the B64Decoder associated with this object.
 // If uninitialized, it will use the global decoder via byteslice.GlobalB64Decoder()
 func (b *Buffer) B64Decoder() B64Decoder {
@@ -61,7 +142,14 @@ func (b *Buffer) SetB64Decoder(dec B64Decoder) *Buffer {
 
 // B64Encoder returns the B64Encoder associated with this object.
 // If uninitialized, will use the global decoder via byteslice.GlobalB64Encoder()
+//
+// If round-trip encoding is enabled via SetRoundTripEncoding(), and the
+// `*base64.Encoding` used by the last decode operation is known, that
+// encoding is returned instead.
 func (b *Buffer) B64Encoder() B64Encoder {
+	if b.roundTrip && b.detected != nil {
+		return b.detected
+	}
 	if b.encoder != nil {
 		return b.encoder
 	}
@@ -69,38 +157,403 @@ func (b *Buffer) B64Encoder() B64Encoder {
 }
 
 // SetB64Encoder assigns a B64Encoder for this object.
-func (b *Buffer) SetEncoder(enc B64Encoder) *Buffer {
+func (b *Buffer) SetB64Encoder(enc B64Encoder) *Buffer {
 	b.encoder = enc
 	return b
 }
 
+// SetB64Codec assigns `c` as both the B64Encoder and the B64Decoder
+// for this object.
+func (b *Buffer) SetB64Codec(c B64Codec) *Buffer {
+	b.SetB64Encoder(c)
+	b.SetB64Decoder(c)
+	return b
+}
+
+// validationVector is a payload that encodes differently in the standard
+// and URL-safe base64 alphabets, and requires padding
+var validationVector = []byte{0xfb, 0xff, 0xfe, 0x00}
+
+// Validate checks that the B64Encoder and B64Decoder associated with this
+// object (or the global ones, if not specified) are consistent with each
+// other, by encoding a small known payload and decoding it again. An error
+// is returned if the decoder fails, or if the result differs from the
+// original payload.
+//
+// This is intended to catch misconfigurations early, such as setting a
+// URL-safe encoder along with a decoder for the standard alphabet.
+// The contents of the buffer are not affected.
+func (b *Buffer) Validate() error {
+	encoded := b.B64Encoder().EncodeToString(validationVector)
+	decoded, err := b.B64Decoder().DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf(`failed to validate byteslice.Buffer: decoder failed to decode %q produced by encoder: %w`, encoded, err)
+	}
+	if !bytes.Equal(decoded, validationVector) {
+		return fmt.Errorf(`failed to validate byteslice.Buffer: encoder and decoder are inconsistent: %x was encoded as %q, and decoded as %x`, validationVector, encoded, decoded)
+	}
+	return nil
+}
+
+// SetRoundTripEncoding specifies if the `*base64.Encoding` used to decode
+// the data should be remembered, and reused when encoding it again.
+// For example, if a URL-safe string without padding is decoded, it will
+// be encoded as a URL-safe string without padding as well.
+//
+// The encoding can only be determined if the decoder is either the
+// default heuristic decoder, or a `*base64.Encoding` object. Otherwise,
+// the encoder is chosen as usual. See also `LastDecodeEncoding()`.
+func (b *Buffer) SetRoundTripEncoding(v bool) *Buffer {
+	b.roundTrip = v
+	return b
+}
+
+// LastDecodeEncoding returns the `*base64.Encoding` object that was used
+// by the last successful decode operation, such as `UnmarshalJSON` or
+// `DecodeString`. When the default heuristic decoder is used, this is
+// the encoding that the heuristics chose.
+//
+// nil is returned if no data has been decoded yet, or if the encoding
+// could not be determined, for example because the decoder is neither
+// the heuristic decoder nor a `*base64.Encoding` object, or because
+// the data was a JSON array.
+func (b *Buffer) LastDecodeEncoding() *base64.Encoding {
+	if b == nil {
+		return nil
+	}
+	return b.detected
+}
+
+// SetMaxDecodeLen specifies the maximum number of bytes that may be
+// stored in the buffer as the result of a decode operation, such as
+// `UnmarshalJSON`, `DecodeString`, or `DecodeFrom`. Inputs that exceed
+// this limit are rejected with an error. A value of 0 (the default)
+// means there is no limit.
+//
+// For the default heuristic decoder and `*base64.Encoding` objects, the
+// decoded length is estimated from the length of the encoded input, so
+// oversized inputs are rejected before any memory is allocated for them.
+// Other decoders are checked after decoding.
+func (b *Buffer) SetMaxDecodeLen(n int) *Buffer {
+	b.maxDecodeLen = n
+	return b
+}
+
+// SetDecodeValidator specifies a function that is called with the result
+// of each decode operation, such as `UnmarshalJSON`, `DecodeString`, or
+// `AcceptValue` with a string. If the function returns an error, the
+// decode operation fails, and the contents of the buffer are left
+// untouched. Pass `nil` to remove the validator.
+//
+// See `ValidateUTF8` for a built-in validator.
+func (b *Buffer) SetDecodeValidator(fn func([]byte) error) *Buffer {
+	b.validator = fn
+	return b
+}
+
+// SetEncoder assigns a B64Encoder for this object.
+//
+// Deprecated: use SetB64Encoder instead.
+func (b *Buffer) SetEncoder(enc B64Encoder) *Buffer {
+	return b.SetB64Encoder(enc)
+}
+
+// SetNilAsNull specifies if a `Buffer` object whose internal `[]byte`
+// is `nil` should be serialized as JSON `null`. By default it is
+// serialized as an empty string, and an empty `[]byte` is always
+// serialized as an empty string.
+//
+// Regardless of this setting, deserializing a JSON `null` sets the
+// internal `[]byte` to `nil`.
+func (b *Buffer) SetNilAsNull(v bool) *Buffer {
+	b.nilAsNull = v
+	return b
+}
+
+// SetJSONArrayMode specifies if `MarshalJSON` should serialize the data
+// as a JSON array of integers, such as `[72,105]`, instead of an encoded
+// string. This is the representation used by some other languages and
+// libraries, such as Rust's serde. An empty or `nil` `[]byte` is
+// serialized as `[]`, unless `SetNilAsNull(true)` applies.
+//
+// `UnmarshalJSON` accepts both representations regardless of this setting.
+func (b *Buffer) SetJSONArrayMode(v bool) *Buffer {
+	b.jsonArray = v
+	return b
+}
+
+// SetSelfDescribing specifies if `MarshalJSON` should prefix the encoded
+// string with the name of the encoding, such as `"base64url:QWxpY2U"`,
+// using the same prefixes that `ParsePrefixed` recognizes. When enabled,
+// `UnmarshalJSON` also strips a recognized prefix, and decodes the rest
+// of the string using the encoding that it names. Strings without a
+// recognized prefix are decoded as usual.
+//
+// `MarshalJSON` returns an error if no prefix is defined for the
+// B64Encoder object in use. Prefixes are defined for the standard and
+// URL-safe base64 encodings with '=' padding or without padding, including
+// their strict variants (such as with `SetRoundTripEncoding(true)` and
+// a decoder from `NewStrictDecoder`), HexEncoder,
+// HexUpperEncoder, Base32StdEncoder, and Base32RawStdEncoder.
+func (b *Buffer) SetSelfDescribing(v bool) *Buffer {
+	b.selfDescribing = v
+	return b
+}
+
 // UnmarshalJSON implements `"encoding/json".Unmarshaler`, and provides
 // a method to deserialize a `[]byte` string from a base64 encoded
 // JSON string.
 //
 // The JSON string will be parsed using the B64Decoder object associated
 // with this object (or the global one, if not specified).
+//
+// A JSON `null` sets the internal `[]byte` to `nil`, discarding any
+// previous contents.
+//
+// A JSON array of integers, such as `[72,105]`, is also accepted, and
+// each element is stored as a single byte. Elements must be in the range
+// 0 to 255.
 func (b *Buffer) UnmarshalJSON(data []byte) error {
 	if b == nil {
 		return fmt.Errorf(`nil byteslice.Buffer`)
 	}
+	return b.UnmarshalJSONWith(data, b.B64Decoder())
+}
+
+// UnmarshalJSONWith works like `UnmarshalJSON`, but decodes the JSON
+// string using `dec` instead of the B64Decoder object associated with
+// this object. The association is not changed.
+//
+// This is useful when implementing `UnmarshalJSON` for a type that
+// contains several `Buffer` fields using different encodings, as the
+// `Buffer` objects created by `"encoding/json"` are not configured:
+//
+//	func (f *Foo) UnmarshalJSON(data []byte) error {
+//	  var raw struct {
+//	    Hex json.RawMessage `json:"hex"`
+//	    URL json.RawMessage `json:"url"`
+//	  }
+//	  if err := json.Unmarshal(data, &raw); err != nil {
+//	    return err
+//	  }
+//	  if err := f.Hex.UnmarshalJSONWith(raw.Hex, byteslice.HexDecoder); err != nil {
+//	    return err
+//	  }
+//	  return f.URL.UnmarshalJSONWith(raw.URL, base64.RawURLEncoding)
+//	}
+//
+// If `dec` is nil, the B64Decoder object associated with this object
+// (or the global one, if not specified) is used.
+func (b *Buffer) UnmarshalJSONWith(data []byte, dec B64Decoder) error {
+	if b == nil {
+		return fmt.Errorf(`nil byteslice.Buffer`)
+	}
+	if dec == nil {
+		dec = b.B64Decoder()
+	}
+
+	if string(data) == `null` {
+		b.data = nil
+		return nil
+	}
+
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+		return b.unmarshalJSONArray(data)
+	}
+
+	if b.selfDescribing {
+		var raw string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf(`failed to unmarshal data to byteslice.Buffer: %w`, err)
+		}
+		if prefixed, rest, ok := splitPrefixed(raw); ok {
+			dec, raw = prefixed, rest
+		}
+		if err := b.decodeAndSetStringWith(dec, raw); err != nil {
+			return fmt.Errorf(`failed to accept unmarshaled data: %w`, err)
+		}
+		return nil
+	}
+
+	if ok, err := b.decodeJSONStringFast(data, dec); ok {
+		if err != nil {
+			return fmt.Errorf(`failed to accept unmarshaled data: %w`, err)
+		}
+		return nil
+	}
 
 	var raw string
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return fmt.Errorf(`failed to unmarshal data to byteslice.Buffer: %w`, err)
 	}
 
-	if err := b.decodeAndSetString(raw); err != nil {
+	if err := b.decodeAndSetStringWith(dec, raw); err != nil {
+		return fmt.Errorf(`failed to accept unmarshaled data: %w`, err)
+	}
+	return nil
+}
+
+// decodeJSONStringFast decodes the JSON string `data` directly from the
+// `[]byte`, without unmarshaling it into an intermediate string first.
+// ok is false if the fast path can not be used, in which case the caller
+// must fall back to the regular path. This is the case if the string
+// contains escape sequences or non-ASCII characters, or if the decoder
+// is neither a `*base64.Encoding` object nor a plain HeuristicDecoder.
+func (b *Buffer) decodeJSONStringFast(data []byte, dec B64Decoder) (ok bool, err error) {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return false, nil
+	}
+	src := data[1 : len(data)-1]
+	for _, c := range src {
+		if c < 0x20 || c == '"' || c == '\\' || c >= utf8.RuneSelf {
+			return false, nil
+		}
+	}
+
+	var buf []byte
+	var enc *base64.Encoding
+	switch dec := dec.(type) {
+	case *base64.Encoding:
+		// encodingPadding allocates, so only look up the padding when needed
+		if b.maxDecodeLen > 0 {
+			if err := b.checkMaxDecodeLen(src, encodingPadding(dec)); err != nil {
+				return true, err
+			}
+		}
+		enc = dec
+		buf = make([]byte, enc.DecodedLen(len(src)))
+		var n int
+		n, err = enc.Decode(buf, src)
+		buf = buf[:n]
+	case *HeuristicDecoder:
+		if err := b.checkMaxDecodeLen(src, dec.paddingChar()); err != nil {
+			return true, err
+		}
+		buf, enc, ok, err = dec.decodeBytesDetect(src)
+		if !ok {
+			return false, nil
+		}
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, fmt.Errorf(`failed to decode string for byteslice.Buffer: %w`, withKind(ErrInvalidEncoding, err))
+	}
+	if err := b.setDecoded(buf, enc); err != nil {
+		return true, err
+	}
+	notifyDecodeObserver(len(src), len(buf), enc)
+	return true, nil
+}
+
+// checkMaxDecodeLen checks the length of the data that the encoded `src`
+// decodes to against the maximum set by SetMaxDecodeLen, the same way
+// decodedLen does for strings, before any memory is allocated for it
+func (b *Buffer) checkMaxDecodeLen(src []byte, padding rune) error {
+	if b.maxDecodeLen <= 0 {
+		return nil
+	}
+	src = bytes.Trim(src, asciiSpace)
+	if padding != base64.NoPadding {
+		src = bytes.TrimRight(src, string(padding))
+	}
+	if l := len(src) * 6 / 8; l > b.maxDecodeLen {
+		return maxDecodeLenError(l, b.maxDecodeLen)
+	}
+	return nil
+}
+
+func maxDecodeLenError(l, max int) error {
+	return withKind(ErrMaxLenExceeded, fmt.Errorf(`failed to decode string for byteslice.Buffer: decoded length %d exceeds maximum of %d bytes`, l, max))
+}
+
+func (b *Buffer) unmarshalJSONArray(data []byte) error {
+	var elements []int
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return fmt.Errorf(`failed to unmarshal data to byteslice.Buffer: %w`, err)
+	}
+
+	buf := make([]byte, len(elements))
+	for i, e := range elements {
+		if e < 0 || e > 255 {
+			return fmt.Errorf(`failed to accept unmarshaled data: %w`, withKind(ErrInvalidEncoding, fmt.Errorf(`array element %d is out of range for a byte: %d`, i, e)))
+		}
+		buf[i] = byte(e)
+	}
+
+	if err := b.setDecoded(buf, nil); err != nil {
 		return fmt.Errorf(`failed to accept unmarshaled data: %w`, err)
 	}
+	if fn := decodeObserver(); fn != nil {
+		fn(len(data), len(buf), "array")
+	}
 	return nil
 }
 
+// DecodeString decodes `s` using the B64Decoder object associated with
+// this object (or the global one, if not specified), and replaces the
+// contents of the buffer with the result. Unlike `UnmarshalJSON`, the
+// string does not need to be quoted.
+//
+// If decoding fails, the contents of the buffer are left untouched.
+func (b *Buffer) DecodeString(s string) error {
+	return b.decodeAndSetString(s)
+}
+
+// DecodeStringStrict is like `DecodeString`, but strictly validates `s`
+// before decoding it, for security sensitive parsing. Whitespace is not
+// ignored, every character must belong to the alphabet, padding may only
+// appear at the end, the length must be valid for the encoding, and any
+// unused trailing bits must be zero. The error reports the position of
+// the offending character.
+//
+// If the B64Decoder object associated with this object (or the global
+// one, if not specified) is a `*base64.Encoding` object, that encoding is
+// used. Otherwise, the encoding is chosen using the same heuristics as
+// HeuristicB64Decoder.
+func (b *Buffer) DecodeStringStrict(s string) error {
+	var dec strictB64Decoder
+	if enc, ok := b.B64Decoder().(*base64.Encoding); ok {
+		dec.enc = enc
+	}
+	return b.decodeAndSetStringWith(dec, s)
+}
+
 func (b *Buffer) decodeAndSetString(in string) error {
-	buf, err := b.B64Decoder().DecodeString(in)
+	return b.decodeAndSetStringWith(b.B64Decoder(), in)
+}
+
+func (b *Buffer) decodeAndSetStringWith(dec B64Decoder, in string) error {
+	if b.maxDecodeLen > 0 {
+		if l, ok := decodedLen(dec, in); ok && l > b.maxDecodeLen {
+			return maxDecodeLenError(l, b.maxDecodeLen)
+		}
+	}
+
+	buf, enc, err := decodeString(dec, in)
 	if err != nil {
-		return fmt.Errorf(`failed to decode string for byteslice.Buffer: %w`, err)
+		return fmt.Errorf(`failed to decode string for byteslice.Buffer: %w`, withKind(ErrInvalidEncoding, err))
+	}
+	if err := b.setDecoded(buf, enc); err != nil {
+		return err
 	}
+	notifyDecodeObserver(len(in), len(buf), enc)
+	return nil
+}
+
+// setDecoded assigns the result of a decode operation. `enc` is the
+// `*base64.Encoding` object used to decode the data, if known.
+func (b *Buffer) setDecoded(buf []byte, enc *base64.Encoding) error {
+	if b.maxDecodeLen > 0 && len(buf) > b.maxDecodeLen {
+		return maxDecodeLenError(len(buf), b.maxDecodeLen)
+	}
+	if b.validator != nil {
+		if err := b.validator(buf); err != nil {
+			return fmt.Errorf(`failed to validate decoded data for byteslice.Buffer: %w`, withKind(ErrValidationFailed, err))
+		}
+	}
+	b.detected = enc
 	b.data = buf
 	return nil
 }
@@ -111,15 +564,244 @@ func (b *Buffer) decodeAndSetString(in string) error {
 //
 // The JSON string will be parsed using the B64Encoder object associated
 // with this object (or the global one, if not specified).
+//
+// This method has a value receiver so that `Buffer` values, and not just
+// pointers, are serialized using it. `Buffer` does not contain a lock,
+// so copying it here does not trigger `go vet`'s copylocks check.
+// For the same reason, serializing a `Buffer` object while another
+// goroutine modifies it is a data race, and must be guarded by the caller.
+//
+// `"encoding/json"` serializes a nil `*Buffer`, such as an unset pointer
+// field, as `null` without calling this method. Calling it directly on a
+// nil `*Buffer` panics, as with any method with a value receiver, and
+// it can not have a pointer receiver counterpart of the same name. Use
+// `json.Marshal()` or the nil-safe `EncodeToString()` if the pointer
+// may be nil.
 func (b Buffer) MarshalJSON() ([]byte, error) {
-	return json.Marshal(b.B64Encoder().EncodeToString(b.data))
+	if b.nilAsNull && b.data == nil {
+		return []byte(`null`), nil
+	}
+	if b.jsonArray {
+		return b.marshalJSONArray(), nil
+	}
+	enc := b.B64Encoder()
+	if b.selfDescribing {
+		prefix, ok := encoderPrefix(enc)
+		if !ok {
+			return nil, fmt.Errorf(`failed to marshal byteslice.Buffer: no self-describing prefix is defined for encoder %T`, enc)
+		}
+		return json.Marshal(prefix + enc.EncodeToString(b.data))
+	}
+	return json.Marshal(enc.EncodeToString(b.data))
+}
+
+func (b *Buffer) marshalJSONArray() []byte {
+	// each element takes at most 4 bytes, including the comma
+	buf := make([]byte, 0, 2+4*len(b.data))
+	buf = append(buf, '[')
+	for i, c := range b.data {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = strconv.AppendUint(buf, uint64(c), 10)
+	}
+	return append(buf, ']')
+}
+
+// UnmarshalText implements `"encoding".TextUnmarshaler`, and provides
+// a method to deserialize a `[]byte` string from base64 encoded text.
+//
+// Unlike `UnmarshalJSON`, the text is not expected to be quoted.
+// Empty input is decoded like any other string, and results in
+// a non-nil, zero-length buffer.
+func (b *Buffer) UnmarshalText(text []byte) error {
+	if b == nil {
+		return fmt.Errorf(`nil byteslice.Buffer`)
+	}
+
+	if err := b.decodeAndSetString(string(text)); err != nil {
+		return fmt.Errorf(`failed to accept unmarshaled text: %w`, err)
+	}
+	return nil
+}
+
+// MarshalText implements `"encoding".TextMarshaler`, and provides
+// a method to serialize a `[]byte` string to base64 encoded text.
+//
+// The text is encoded using the B64Encoder object associated with
+// this object (or the global one, if not specified), and is not quoted.
+func (b Buffer) MarshalText() ([]byte, error) {
+	return b.AppendText(nil)
+}
+
+// AppendText implements `"encoding".TextAppender` (Go 1.24 and later),
+// and appends the same text that `MarshalText` would return to `dst`,
+// without allocating an intermediate `[]byte`. It never returns an error.
+//
+// Like `MarshalText`, this method has a value receiver.
+func (b Buffer) AppendText(dst []byte) ([]byte, error) {
+	return b.AppendEncoded(dst), nil
+}
+
+// UnmarshalXML implements `"encoding/xml".Unmarshaler`, and decodes the
+// base64 encoded character data of the element using the B64Decoder
+// object associated with this object (or the global one, if not specified).
+// An empty element results in a zero-length buffer.
+func (b *Buffer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if b == nil {
+		return fmt.Errorf(`nil byteslice.Buffer`)
+	}
+
+	var raw string
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return fmt.Errorf(`failed to unmarshal XML element to byteslice.Buffer: %w`, err)
+	}
+	return b.UnmarshalText([]byte(raw))
+}
+
+// MarshalXML implements `"encoding/xml".Marshaler`, and encodes the
+// buffer as the base64 encoded character data of the element using the
+// B64Encoder object associated with this object (or the global one, if
+// not specified).
+func (b Buffer) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(b.EncodeToString(), start)
+}
+
+// MarshalBinary implements `"encoding".BinaryMarshaler`, and returns
+// a copy of the raw bytes stored in the `Buffer` object. Unlike the
+// JSON and text forms, the binary form is not base64 encoded.
+//
+// Binary codecs that honor this interface, such as
+// `github.com/fxamacker/cbor/v2`, serialize the buffer as a native
+// byte string.
+func (b Buffer) MarshalBinary() ([]byte, error) {
+	data := make([]byte, len(b.data))
+	copy(data, b.data)
+	return data, nil
+}
+
+// UnmarshalBinary implements `"encoding".BinaryUnmarshaler`, and copies
+// the raw bytes in `data` to the internal buffer. Unlike the JSON and
+// text forms, the binary form is not base64 decoded.
+func (b *Buffer) UnmarshalBinary(data []byte) error {
+	if b == nil {
+		return fmt.Errorf(`nil byteslice.Buffer`)
+	}
+	b.SetBytes(data)
+	return nil
+}
+
+// GobEncode implements `"encoding/gob".GobEncoder`, and returns the same
+// raw bytes as `MarshalBinary()`, avoiding the size overhead of base64.
+func (b Buffer) GobEncode() ([]byte, error) {
+	return b.MarshalBinary()
+}
+
+// GobDecode implements `"encoding/gob".GobDecoder`, and copies the raw
+// bytes in `data` to the internal buffer, as `UnmarshalBinary()` does.
+func (b *Buffer) GobDecode(data []byte) error {
+	return b.UnmarshalBinary(data)
+}
+
+// Scan implements `"database/sql".Scanner`.
+//
+// Values returned from database drivers are raw bytes, so unlike
+// `AcceptValue`, `[]byte` and `string` sources are copied as-is and
+// are NOT base64 decoded. A `nil` source sets the internal `[]byte`
+// to `nil`.
+func (b *Buffer) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		b.data = nil
+		return nil
+	case []byte:
+		b.SetBytes(src)
+		return nil
+	case string:
+		b.SetBytes([]byte(src))
+		return nil
+	default:
+		return fmt.Errorf(`failed to scan value into byteslice.Buffer: can't handle type %T`, src)
+	}
+}
+
+// Value implements `"database/sql/driver".Valuer`, and returns the
+// raw bytes stored in the `Buffer` object. If the internal `[]byte`
+// is `nil`, `nil` is returned so that it is stored as NULL.
+func (b Buffer) Value() (driver.Value, error) {
+	if b.data == nil {
+		return nil, nil
+	}
+	return b.data, nil
+}
+
+// String implements `fmt.Stringer`, and returns the same value as
+// `EncodeToString()`.
+//
+// This method has a pointer receiver so that it can be called on a nil
+// `*Buffer`. When printing with the "fmt" package, `Buffer` values, such
+// as struct fields, are handled by `Format` instead, which produces the
+// same output for %s and %v.
+func (b *Buffer) String() string {
+	return b.EncodeToString()
+}
+
+// EncodeToString returns the base64 encoded form of the buffer using
+// the B64Encoder object associated with this object (or the global one,
+// if not specified). Unlike `MarshalJSON`, the result is not quoted.
+func (b *Buffer) EncodeToString() string {
+	if b == nil {
+		return ""
+	}
+	return b.B64Encoder().EncodeToString(b.data)
+}
+
+// AppendEncoded appends the base64 encoded form of the buffer to `dst`,
+// and returns the extended slice. The data is encoded using the B64Encoder
+// object associated with this object (or the global one, if not specified),
+// and is not quoted.
+//
+// If the encoder implements AppendEncoder, or is a `*base64.Encoding`
+// object, the data is encoded directly into `dst`, without allocating
+// an intermediate string.
+func (b *Buffer) AppendEncoded(dst []byte) []byte {
+	if b == nil {
+		return dst
+	}
+
+	switch enc := b.B64Encoder().(type) {
+	case AppendEncoder:
+		return enc.AppendEncode(dst, b.data)
+	case *base64.Encoding:
+		n := enc.EncodedLen(len(b.data))
+		l := len(dst)
+		if cap(dst)-l < n {
+			grown := make([]byte, l, l+n)
+			copy(grown, dst)
+			dst = grown
+		}
+		dst = dst[:l+n]
+		enc.Encode(dst[l:], b.data)
+		return dst
+	default:
+		return append(dst, enc.EncodeToString(b.data)...)
+	}
 }
 
 // Bytes returns the raw bytes stored in the `Buffer` object.
 //
 // Users need to take care of synchronization or acting upon on the
 // returned buffer, as it will affect the actual stored `[]byte` field
-// in the `Buffer` object.
+// in the `Buffer` object. Use `BytesCopy()` if you need a copy that
+// is safe to modify and retain.
+//
+// Bytes preserves the distinction between nil and empty data. It returns
+// nil only if no data has been assigned, as is the case for the zero value,
+// `New(nil)`, after `Wipe()`, or after unmarshaling a JSON `null`. Once
+// data has been assigned, an empty buffer returns a non-nil slice of
+// length 0, as is the case for `New([]byte{})`, `SetBytes([]byte{})`, or
+// after decoding an empty string. `Reset()` keeps whichever state the
+// buffer was in. Use `BytesOrNil()` if you need nil for all empty buffers.
 func (b *Buffer) Bytes() []byte {
 	if b == nil {
 		return nil
@@ -127,28 +809,71 @@ func (b *Buffer) Bytes() []byte {
 	return b.data
 }
 
+// BytesOrNil is like `Bytes()`, but returns nil if the buffer is empty,
+// regardless of whether data has been assigned or not.
+func (b *Buffer) BytesOrNil() []byte {
+	if b == nil || len(b.data) == 0 {
+		return nil
+	}
+	return b.data
+}
+
+// BytesCopy returns a copy of the raw bytes stored in the `Buffer` object.
+// Unlike `Bytes()`, the returned `[]byte` does not share storage with
+// the `Buffer` object, and is safe to modify and retain.
+func (b *Buffer) BytesCopy() []byte {
+	if b == nil || b.data == nil {
+		return nil
+	}
+
+	data := make([]byte, len(b.data))
+	copy(data, b.data)
+	return data
+}
+
 // AcceptValue is used in by some consumers to assign the value
 // whose type is not known before hand.
 //
 // Values can be either one of the following types: `*byteslice.Buffer`,
-// `[]byte`, or `string`.
+// `[]byte`, `*[]byte`, `json.RawMessage`, `string`, or `nil`.
 //
 // If the value is a `*byteslice.Buffer`, a copy of the underlying
 // is created, and assigned to receiver.
 //
-// If the value is a `[]byte`, it is the same as calling `SetBytes()`
+// If the value is a `[]byte`, it is the same as calling `SetBytes()`.
+// If the value is a `*[]byte`, it is dereferenced first.
+//
+// If the value is a `json.RawMessage`, it is treated as a JSON value,
+// and is the same as calling `UnmarshalJSON()`.
 //
 // IF the value is a `string`, the string is assumed to be a base64-encoded
 // string. Unlike in the case of `UnmarshalJSON`, the string does not need
 // to be quoted.
+//
+// If the value is `nil`, the internal `[]byte` is set to `nil`.
 func (b *Buffer) AcceptValue(in interface{}) error {
 	switch in := in.(type) {
+	case nil:
+		b.data = nil
+		return nil
 	case *Buffer:
-		b.SetBytes(in.Bytes())
+		b.AcceptBuffer(in, false)
 		return nil
 	case []byte:
 		b.SetBytes(in)
 		return nil
+	case *[]byte:
+		if in == nil {
+			b.data = nil
+			return nil
+		}
+		b.SetBytes(*in)
+		return nil
+	case json.RawMessage:
+		if err := b.UnmarshalJSON(in); err != nil {
+			return fmt.Errorf(`failed to accept value for byteslice.Buffer: %w`, err)
+		}
+		return nil
 	case string:
 		if err := b.decodeAndSetString(in); err != nil {
 			return fmt.Errorf(`failed to accept value for byteslice.Buffer: %w`, err)
@@ -159,10 +884,43 @@ func (b *Buffer) AcceptValue(in interface{}) error {
 	}
 }
 
-// SetBytes copies the `data` byte slice to the internal buffer
+// AcceptBuffer copies the contents of `src` to the receiver. If `copyConfig`
+// is true, the per-instance configuration of `src` is also carried over:
+// the encoder and decoder, the settings made by `SetNilAsNull`,
+// `SetJSONArrayMode`, `SetRoundTripEncoding`, `SetSelfDescribing`, and
+// `SetMaxDecodeLen`, and the decode validator. The result of the last
+// decode operation (see `LastDecodeEncoding()`) is state rather than
+// configuration, and is not copied. If `copyConfig` is false, the
+// receiver's configuration is left untouched, which is what `AcceptValue` does.
+//
+// As `Buffer` objects are not synchronized, the caller must make sure
+// that `src` is not modified concurrently.
+func (b *Buffer) AcceptBuffer(src *Buffer, copyConfig bool) {
+	if copyConfig && src != nil {
+		b.encoder = src.encoder
+		b.decoder = src.decoder
+		b.nilAsNull = src.nilAsNull
+		b.jsonArray = src.jsonArray
+		b.roundTrip = src.roundTrip
+		b.selfDescribing = src.selfDescribing
+		b.maxDecodeLen = src.maxDecodeLen
+		b.validator = src.validator
+	}
+	b.SetBytes(src.Bytes())
+}
+
+// CopyTo copies the contents of the buffer into `dst`, and returns the
+// number of bytes copied, which is the minimum of `len(dst)` and `Len()`.
+// Like the builtin `copy`, it does not allocate.
+func (b *Buffer) CopyTo(dst []byte) int {
+	return copy(dst, b.Bytes())
+}
+
+// SetBytes copies the `data` byte slice to the internal buffer.
+// Passing a non-nil empty slice leaves the internal buffer empty but non-nil.
 func (b *Buffer) SetBytes(data []byte) {
 	l := len(data)
-	if cap(b.data) < l {
+	if cap(b.data) < l || (b.data == nil && data != nil) {
 		b.data = make([]byte, l)
 	} else {
 		b.data = b.data[:l]
@@ -170,6 +928,36 @@ func (b *Buffer) SetBytes(data []byte) {
 	copy(b.data, data)
 }
 
+// SetBytesNoCopy adopts `data` as the internal buffer as is, without
+// copying it. This is the counterpart of `SetBytes`, much like
+// `WithRawCopy(false)` is for `New()`, and is useful when ownership of
+// a freshly allocated slice is being handed over to the `Buffer` object.
+//
+// The caller must not modify or reuse `data` afterwards, as any changes
+// are visible through the buffer.
+func (b *Buffer) SetBytesNoCopy(data []byte) {
+	b.data = data
+}
+
+// Swap replaces the contents of the buffer with a copy of `data`,
+// and returns the previous contents. Ownership of the returned slice
+// is transferred to the caller, as the buffer no longer refers to it.
+// Passing nil leaves the buffer with no data assigned.
+//
+// As `Buffer` objects are not synchronized, Swap does not make the
+// replacement atomic with respect to other goroutines. If the buffer
+// is shared, the caller must guard the call with their own lock.
+func (b *Buffer) Swap(data []byte) []byte {
+	old := b.data
+	if data == nil {
+		b.data = nil
+	} else {
+		b.data = make([]byte, len(data))
+		copy(b.data, data)
+	}
+	return old
+}
+
 // Len returns the length of the internal `[]byte` buffer
 func (b *Buffer) Len() int {
 	if b == nil {
@@ -177,3 +965,413 @@ func (b *Buffer) Len() int {
 	}
 	return len(b.data)
 }
+
+// Cap returns the capacity of the internal `[]byte` buffer
+func (b *Buffer) Cap() int {
+	if b == nil {
+		return 0
+	}
+	return cap(b.data)
+}
+
+// IsText returns true if the contents of the buffer are likely to be text:
+// that is, they are valid UTF-8, and contain no control characters other
+// than tabs, carriage returns, and newlines. An empty buffer is considered
+// to be text.
+func (b *Buffer) IsText() bool {
+	data := b.Bytes()
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size <= 1 {
+			return false
+		}
+		if unicode.IsControl(r) && r != '\t' && r != '\r' && r != '\n' {
+			return false
+		}
+		data = data[size:]
+	}
+	return true
+}
+
+// SuggestEncoding inspects the contents of the buffer, and returns a hint
+// for the most compact textual encoding that can carry them:
+//
+//   - "ascii" if the contents are printable ASCII (tabs, carriage returns,
+//     and newlines are allowed), and could be sent as-is without base64
+//   - "base64url" if the base64 encoded contents contain neither '+' nor '/',
+//     so that the output of the URL-safe and standard alphabets are the same,
+//     and the unpadded form can be used in URLs without escaping
+//   - "base64" otherwise
+//
+// This is an advisory heuristic only: it does not change how the buffer
+// is encoded, and the caller is free to ignore it.
+func (b *Buffer) SuggestEncoding() string {
+	data := b.Bytes()
+	if isPrintableASCII(data) {
+		return "ascii"
+	}
+	if isURLSafeBase64(data) {
+		return "base64url"
+	}
+	return "base64"
+}
+
+func isPrintableASCII(data []byte) bool {
+	for _, c := range data {
+		if (c < 0x20 || c > 0x7e) && c != '\t' && c != '\r' && c != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+// isURLSafeBase64 reports whether encoding data in base64 yields no
+// characters from positions 62 and 63 of the alphabet ('+' and '/' in
+// the standard alphabet)
+func isURLSafeBase64(data []byte) bool {
+	for len(data) > 0 {
+		var group [3]byte
+		n := copy(group[:], data)
+		data = data[n:]
+
+		v := uint(group[0])<<16 | uint(group[1])<<8 | uint(group[2])
+		// a partial group of n bytes produces n+1 characters
+		for i := 0; i <= n; i++ {
+			if (v>>(18-6*uint(i)))&0x3f >= 62 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsNil returns true if the `Buffer` object is `nil`, or its internal
+// `[]byte` is `nil`.
+func (b *Buffer) IsNil() bool {
+	return b == nil || b.data == nil
+}
+
+// IsEmpty returns true if the length of the internal `[]byte` is zero,
+// regardless of whether it is `nil` or not.
+func (b *Buffer) IsEmpty() bool {
+	return b.Len() == 0
+}
+
+// Reset returns the `Buffer` object to its zero state. The contents
+// are truncated to zero length, but the capacity of the internal
+// `[]byte` is retained for reuse. All per-instance configuration is
+// cleared as well:
+//
+//   - the encoder and decoder, so the global ones will be used afterwards
+//   - the settings made by `SetNilAsNull`, `SetJSONArrayMode`,
+//     `SetRoundTripEncoding`, and `SetSelfDescribing`
+//   - the maximum decode length set by `SetMaxDecodeLen`
+//   - the decode validator set by `SetDecodeValidator`
+//   - the encoding reported by `LastDecodeEncoding()`
+//
+// Callers that reuse buffers, such as from a BufferPool, must configure
+// them again after calling Reset. Calling Reset on a nil `*Buffer` is a no-op.
+func (b *Buffer) Reset() {
+	if b == nil {
+		return
+	}
+	*b = Buffer{data: b.data[:0]}
+}
+
+// Clone creates a copy of the `Buffer` object. The contents are copied
+// into a newly allocated `[]byte`, so the returned object does not share
+// storage with the receiver. Per-instance encoders and decoders are
+// carried over to the new object.
+func (b *Buffer) Clone() *Buffer {
+	if b == nil {
+		return nil
+	}
+
+	clone := *b
+	if b.data != nil {
+		clone.data = make([]byte, len(b.data))
+		copy(clone.data, b.data)
+	}
+	return &clone
+}
+
+// Equal reports whether the receiver and `other` hold the same bytes.
+// A `nil` `Buffer` object is treated as being empty.
+func (b *Buffer) Equal(other *Buffer) bool {
+	return bytes.Equal(b.Bytes(), other.Bytes())
+}
+
+// EqualBytes reports whether the receiver holds the same bytes as `data`.
+// A `nil` `Buffer` object is treated as being empty.
+func (b *Buffer) EqualBytes(data []byte) bool {
+	return bytes.Equal(b.Bytes(), data)
+}
+
+// EqualString reports whether the receiver holds the same bytes that
+// `s` decodes to, using the B64Decoder object associated with this object
+// (or the global one, if not specified). false is returned if `s` can not
+// be decoded. A `nil` `Buffer` object is treated as being empty, and uses
+// the global decoder.
+func (b *Buffer) EqualString(s string) bool {
+	dec := GlobalB64Decoder()
+	if b != nil {
+		dec = b.B64Decoder()
+	}
+	decoded, err := dec.DecodeString(s)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(b.Bytes(), decoded)
+}
+
+// Compare returns an integer comparing the contents of the receiver and
+// `other` lexicographically, as `"bytes".Compare` does. The result is 0
+// if both are equal, -1 if the receiver is less than `other`, and +1
+// otherwise. A `nil` `Buffer` object is treated as being empty.
+func (b *Buffer) Compare(other *Buffer) int {
+	return bytes.Compare(b.Bytes(), other.Bytes())
+}
+
+// EqualConstantTime is like Equal, but compares the contents using
+// `"crypto/subtle".ConstantTimeCompare`. Use this when comparing
+// secret material such as HMAC keys. Note that the time taken still
+// depends on whether the lengths of the two buffers match.
+func (b *Buffer) EqualConstantTime(other *Buffer) bool {
+	return subtle.ConstantTimeCompare(b.Bytes(), other.Bytes()) == 1
+}
+
+// Wipe overwrites the entire backing array of the internal `[]byte`
+// with zeros, and sets it to `nil`. Use this to clear secret material
+// such as cryptographic keys once they are no longer needed.
+//
+// It is safe to call Wipe multiple times, or on a `nil` `Buffer` object.
+func (b *Buffer) Wipe() {
+	if b == nil {
+		return
+	}
+
+	data := b.data[:cap(b.data)]
+	for i := range data {
+		data[i] = 0
+	}
+	b.data = nil
+}
+
+// Clear overwrites the entire backing array of the internal `[]byte`
+// with zeros, and truncates it to zero length. Unlike `Wipe`, the capacity
+// is retained for reuse, and unlike `Reset`, per-instance configuration
+// such as encoders and decoders is kept.
+//
+// It is safe to call Clear on a `nil` `Buffer` object.
+func (b *Buffer) Clear() {
+	if b == nil {
+		return
+	}
+
+	data := b.data[:cap(b.data)]
+	for i := range data {
+		data[i] = 0
+	}
+	b.data = b.data[:0]
+}
+
+// WriteString appends the contents of `s` to the internal `[]byte`,
+// without converting it to a `[]byte` first. It always returns
+// `len(s), nil`.
+func (b *Buffer) WriteString(s string) (int, error) {
+	b.data = append(b.data, s...)
+	return len(s), nil
+}
+
+// Write implements `io.Writer`, and appends the contents of `p` to
+// the internal `[]byte`. It always returns `len(p), nil`.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+// Reader returns an `io.Reader` over a snapshot of the current contents.
+// The snapshot is a copy, so subsequent modifications to the `Buffer`
+// object do not affect the returned reader. Each call returns a new
+// reader with its own offset.
+func (b *Buffer) Reader() io.Reader {
+	data := make([]byte, b.Len())
+	copy(data, b.Bytes())
+	return bytes.NewReader(data)
+}
+
+// Append appends `data` to the internal `[]byte`, growing it as needed.
+// The receiver is returned so that calls can be chained.
+func (b *Buffer) Append(data ...byte) *Buffer {
+	b.data = append(b.data, data...)
+	return b
+}
+
+// Prepend inserts `data` at the front of the internal `[]byte`, shifting
+// the existing contents towards the end, and growing it as needed. This
+// is the same as `InsertAt(0, data)`. The receiver is returned so that
+// calls can be chained.
+func (b *Buffer) Prepend(data ...byte) *Buffer {
+	// position 0 is always in range
+	_ = b.InsertAt(0, data)
+	return b
+}
+
+// AppendString appends the bytes in `s` to the internal `[]byte`,
+// growing it as needed. The receiver is returned so that calls can
+// be chained.
+func (b *Buffer) AppendString(s string) *Buffer {
+	b.data = append(b.data, s...)
+	return b
+}
+
+// InsertAt inserts `data` at position `pos` of the internal `[]byte`,
+// shifting the bytes after it towards the end, and growing the buffer
+// as needed. `pos` must be in the range `[0:Len()]`, otherwise an
+// error is returned.
+//
+// `data` must not overlap with the internal `[]byte`, such as a slice
+// obtained via `Bytes()`. Use `BytesCopy()` in that case.
+func (b *Buffer) InsertAt(pos int, data []byte) error {
+	l := len(b.data)
+	if pos < 0 || pos > l {
+		return fmt.Errorf(`failed to insert into byteslice.Buffer: position %d out of range [0:%d]`, pos, l)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	if cap(b.data)-l < len(data) {
+		grown := make([]byte, l+len(data), 2*l+len(data))
+		copy(grown, b.data[:pos])
+		copy(grown[pos+len(data):], b.data[pos:])
+		b.data = grown
+	} else {
+		b.data = b.data[:l+len(data)]
+		copy(b.data[pos+len(data):], b.data[pos:l])
+	}
+	copy(b.data[pos:], data)
+	return nil
+}
+
+// DeleteRange removes the bytes in the range `[low:high]` from the
+// internal `[]byte`, shifting the bytes after it towards the front.
+// The capacity is retained. The indices must satisfy
+// `0 <= low <= high <= Len()`, otherwise an error is returned.
+func (b *Buffer) DeleteRange(low, high int) error {
+	if low < 0 || high < low || high > len(b.data) {
+		return fmt.Errorf(`failed to delete from byteslice.Buffer: range [%d:%d] out of range [0:%d]`, low, high, len(b.data))
+	}
+	b.data = append(b.data[:low], b.data[high:]...)
+	return nil
+}
+
+// Truncate discards all but the first `n` bytes of the internal `[]byte`.
+// The capacity is retained, so that subsequent appends may reuse it.
+//
+// As with `"bytes".Buffer`, Truncate panics if `n` is negative or
+// greater than the length of the buffer.
+func (b *Buffer) Truncate(n int) {
+	if n < 0 || n > len(b.data) {
+		panic(`byteslice.Buffer: truncation out of range`)
+	}
+	b.data = b.data[:n]
+}
+
+// Grow grows the capacity of the internal `[]byte`, if necessary, to
+// guarantee space for another `n` bytes. After Grow(n), at least `n`
+// bytes can be appended without another allocation.
+//
+// As with `"bytes".Buffer`, Grow panics if `n` is negative.
+func (b *Buffer) Grow(n int) {
+	if n < 0 {
+		panic(`byteslice.Buffer: negative count`)
+	}
+	if cap(b.data)-len(b.data) >= n {
+		return
+	}
+
+	data := make([]byte, len(b.data), len(b.data)+n)
+	copy(data, b.data)
+	b.data = data
+}
+
+// Slice returns a new `Buffer` object containing a copy of the bytes
+// in the range `[low:high]`. Per-instance configuration such as encoders
+// and decoders are carried over to the new object.
+//
+// As with Go slice expressions, Slice panics if the indices are out of range.
+func (b *Buffer) Slice(low, high int) *Buffer {
+	v := b.SliceView(low, high)
+	v.data = append([]byte(nil), v.data...)
+	return v
+}
+
+// SliceView is like Slice, but the returned `Buffer` object shares the
+// backing array with the receiver, instead of holding a copy. Modifying
+// the bytes in one of them is visible through the other, until either
+// of them reallocates its internal `[]byte`. The capacity of the view is
+// limited to its length, so appending to it never overwrites bytes
+// that follow the range in the receiver.
+//
+// As with Go slice expressions, SliceView panics if the indices are out of range.
+func (b *Buffer) SliceView(low, high int) *Buffer {
+	v := *b
+	v.data = b.data[low:high:high]
+	return &v
+}
+
+// Split slices the contents of the buffer into all subslices separated by
+// `sep`, with the same semantics as `"bytes".Split`, and returns a new
+// `Buffer` object for each of them. Each object holds a copy of the bytes,
+// and carries over per-instance configuration such as encoders and
+// decoders, as `Slice` does.
+func (b *Buffer) Split(sep []byte) []*Buffer {
+	var tmpl Buffer
+	if b != nil {
+		tmpl = *b
+	}
+
+	parts := bytes.Split(tmpl.data, sep)
+	bufs := make([]*Buffer, len(parts))
+	for i, part := range parts {
+		v := tmpl
+		v.data = append([]byte{}, part...)
+		bufs[i] = &v
+	}
+	return bufs
+}
+
+// XorInPlace XORs `key` over the contents of the buffer in place. If
+// `key` is shorter than the buffer, it is repeated; if it is longer,
+// the excess is ignored. Applying the same key twice restores the
+// original contents. An empty key leaves the buffer unchanged.
+func (b *Buffer) XorInPlace(key []byte) {
+	if len(key) == 0 {
+		return
+	}
+	data := b.Bytes()
+	for i := range data {
+		data[i] ^= key[i%len(key)]
+	}
+}
+
+// Sum writes the contents of the buffer to `h`, and returns the
+// resulting digest as computed by `h.Sum(nil)`. The hash is not
+// reset before writing.
+func (b *Buffer) Sum(h hash.Hash) []byte {
+	h.Write(b.Bytes())
+	return h.Sum(nil)
+}
+
+// SHA256 returns the SHA256 digest of the contents of the buffer.
+func (b *Buffer) SHA256() [32]byte {
+	return sha256.Sum256(b.Bytes())
+}
+
+// HexDump returns a hex dump of the contents of the buffer, in the same
+// format as `"encoding/hex".Dump` and the output of `hexdump -C`. This is
+// intended for debugging purposes.
+func (b *Buffer) HexDump() string {
+	return hex.Dump(b.Bytes())
+}
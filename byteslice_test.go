@@ -1,10 +1,21 @@
 package byteslice_test
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/gob"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/lestrrat-go/byteslice"
@@ -54,7 +65,7 @@ func TestAll(t *testing.T) {
 			t.Run(string(dst), func(t *testing.T) {
 				var v byteslice.Buffer
 				v.SetBytes(message)
-				v.SetEncoder(enc)
+				v.SetB64Encoder(enc)
 				buf, err := json.Marshal(v)
 				require.NoError(t, err, `json.Marshal should succeed`)
 				require.Equal(t, buf, dst, `encoded values should match`)
@@ -73,3 +84,2255 @@ func TestStruct(t *testing.T) {
 	require.NoError(t, json.Unmarshal([]byte(src), &foo))
 	require.Equal(t, string(foo.Bar.Bytes()), `Alice`)
 }
+
+func TestAcceptValue(t *testing.T) {
+	t.Run("Repeated", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.AcceptValue([]byte(`Alice`)), `first AcceptValue should succeed`)
+		require.Equal(t, []byte(`Alice`), v.Bytes())
+		require.NoError(t, v.AcceptValue(`Qm9i`), `second AcceptValue should succeed`)
+		require.Equal(t, []byte(`Bob`), v.Bytes())
+	})
+	t.Run("Concurrent", func(t *testing.T) {
+		src := byteslice.New([]byte(`Alice`))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var v byteslice.Buffer
+				for j := 0; j < 100; j++ {
+					if err := v.AcceptValue(src); err != nil {
+						t.Errorf(`AcceptValue should succeed: %s`, err)
+						return
+					}
+					if err := v.AcceptValue(`QWxpY2U`); err != nil {
+						t.Errorf(`AcceptValue should succeed: %s`, err)
+						return
+					}
+					if string(v.Bytes()) != `Alice` {
+						t.Errorf(`expected "Alice", got %q`, v.Bytes())
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestSetBytes(t *testing.T) {
+	t.Run("Shrink", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetBytes([]byte(`abcdef`))
+		require.Equal(t, 6, v.Len())
+		require.Equal(t, []byte(`abcdef`), v.Bytes())
+
+		v.SetBytes([]byte(`xy`))
+		require.Equal(t, 2, v.Len(), `length should match the new data`)
+		require.Equal(t, []byte(`xy`), v.Bytes(), `no stale bytes should remain`)
+	})
+	t.Run("Grow", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetBytes([]byte(`xy`))
+		v.SetBytes([]byte(`abcdef`))
+		require.Equal(t, 6, v.Len())
+		require.Equal(t, []byte(`abcdef`), v.Bytes())
+	})
+}
+
+func TestSetEncoder(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0xfe}
+
+	var v1 byteslice.Buffer
+	v1.SetBytes(data)
+	require.Equal(t, &v1, v1.SetB64Encoder(base64.RawURLEncoding), `SetB64Encoder should return the receiver`)
+
+	var v2 byteslice.Buffer
+	v2.SetBytes(data)
+	require.Equal(t, &v2, v2.SetEncoder(base64.RawURLEncoding), `SetEncoder should return the receiver`)
+
+	buf1, err := json.Marshal(v1)
+	require.NoError(t, err, `json.Marshal should succeed`)
+	buf2, err := json.Marshal(v2)
+	require.NoError(t, err, `json.Marshal should succeed`)
+	require.Equal(t, buf1, buf2, `both setters should produce the same output`)
+	require.Equal(t, []byte(`"-__-"`), buf1)
+}
+
+type upperCodec struct{}
+
+func (upperCodec) EncodeToString(src []byte) string {
+	return strings.ToUpper(string(src))
+}
+
+func (upperCodec) DecodeString(src string) ([]byte, error) {
+	return []byte(strings.ToLower(src)), nil
+}
+
+func TestSetB64Codec(t *testing.T) {
+	t.Run("base64", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Codec(base64.RawURLEncoding)
+		require.Equal(t, base64.RawURLEncoding, v.B64Encoder())
+		require.Equal(t, base64.RawURLEncoding, v.B64Decoder())
+	})
+	t.Run("custom", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Codec(upperCodec{})
+
+		require.NoError(t, json.Unmarshal([]byte(`"ALICE"`), &v), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte(`alice`), v.Bytes(), `decoding should use the codec`)
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"ALICE"`, string(buf), `encoding should use the codec`)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	testcases := []struct {
+		Name    string
+		Encoder byteslice.B64Encoder
+		Decoder byteslice.B64Decoder
+		Error   bool
+	}{
+		{Name: "default"},
+		{Name: "URL encoder with heuristic decoder", Encoder: base64.RawURLEncoding},
+		{Name: "matching pair", Encoder: base64.URLEncoding, Decoder: base64.URLEncoding},
+		{Name: "hex", Encoder: byteslice.HexEncoder, Decoder: byteslice.HexDecoder},
+		{Name: "URL encoder with standard decoder", Encoder: base64.URLEncoding, Decoder: base64.StdEncoding, Error: true},
+		{Name: "standard encoder with URL decoder", Encoder: base64.StdEncoding, Decoder: base64.URLEncoding, Error: true},
+		{Name: "hex encoder with heuristic decoder", Encoder: byteslice.HexEncoder, Error: true},
+		{Name: "mismatched codec", Encoder: upperCodec{}, Decoder: upperCodec{}, Error: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			var v byteslice.Buffer
+			v.SetB64Encoder(tc.Encoder)
+			v.SetB64Decoder(tc.Decoder)
+			if tc.Error {
+				require.Error(t, v.Validate(), `Validate should fail`)
+				return
+			}
+			require.NoError(t, v.Validate(), `Validate should succeed`)
+		})
+	}
+}
+
+func TestFuncCodecs(t *testing.T) {
+	t.Run("marshal through B64EncoderFunc", func(t *testing.T) {
+		v := byteslice.New([]byte{0xfb, 0xff, 0xfe})
+		v.SetB64Encoder(byteslice.B64EncoderFunc(func(src []byte) string {
+			return `b64u:` + base64.RawURLEncoding.EncodeToString(src)
+		}))
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"b64u:-__-"`, string(buf))
+	})
+	t.Run("unmarshal through B64DecoderFunc", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Decoder(byteslice.B64DecoderFunc(func(src string) ([]byte, error) {
+			return base64.RawURLEncoding.DecodeString(strings.TrimPrefix(src, `b64u:`))
+		}))
+		require.NoError(t, json.Unmarshal([]byte(`"b64u:-__-"`), &v), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte{0xfb, 0xff, 0xfe}, v.Bytes())
+	})
+	t.Run("nil functions", func(t *testing.T) {
+		_, err := byteslice.B64DecoderFunc(nil).DecodeString(`QQ==`)
+		require.Error(t, err, `nil B64DecoderFunc should return an error`)
+
+		require.PanicsWithValue(t, `byteslice.B64EncoderFunc: nil function`, func() {
+			byteslice.B64EncoderFunc(nil).EncodeToString([]byte(`A`))
+		})
+	})
+}
+
+func TestReset(t *testing.T) {
+	var v byteslice.Buffer
+	v.SetBytes([]byte(`Alice`))
+	v.SetB64Encoder(base64.RawURLEncoding)
+	v.SetB64Decoder(base64.RawURLEncoding)
+
+	v.SetJSONArrayMode(true).SetMaxDecodeLen(1)
+
+	v.Reset()
+	require.Equal(t, 0, v.Len(), `Len should be 0 after Reset`)
+	require.Equal(t, byteslice.GlobalB64Encoder(), v.B64Encoder(), `B64Encoder should fall back to the global encoder`)
+
+	require.NoError(t, v.DecodeString(`QWxpY2U=`), `maximum decode length should be cleared`)
+	buf, err := json.Marshal(v)
+	require.NoError(t, err, `json.Marshal should succeed`)
+	require.Equal(t, `"QWxpY2U="`, string(buf), `JSON array mode should be cleared`)
+
+	t.Run("nil", func(t *testing.T) {
+		var v *byteslice.Buffer
+		require.NotPanics(t, v.Reset, `Reset should be a no-op on a nil Buffer`)
+	})
+}
+
+func TestText(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.SetB64Encoder(base64.RawURLEncoding)
+
+		text, err := v.MarshalText()
+		require.NoError(t, err, `MarshalText should succeed`)
+		require.Equal(t, []byte(`QWxpY2U`), text)
+	})
+	t.Run("Marshal empty", func(t *testing.T) {
+		var v byteslice.Buffer
+		text, err := v.MarshalText()
+		require.NoError(t, err, `MarshalText should succeed`)
+		require.Equal(t, ``, string(text))
+	})
+	t.Run("AppendText", func(t *testing.T) {
+		for _, enc := range []byteslice.B64Encoder{base64.StdEncoding, base64.RawURLEncoding, byteslice.HexEncoder} {
+			v := byteslice.New([]byte{0xfb, 0xff, 0xfe, 'A'}, byteslice.WithB64Encoder(enc))
+
+			text, err := v.MarshalText()
+			require.NoError(t, err, `MarshalText should succeed`)
+
+			appended, err := v.AppendText([]byte(`prefix:`))
+			require.NoError(t, err, `AppendText should succeed`)
+			require.Equal(t, `prefix:`+string(text), string(appended))
+		}
+	})
+	t.Run("Unmarshal", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.UnmarshalText([]byte(`QWxpY2U=`)), `UnmarshalText should succeed`)
+		require.Equal(t, []byte(`Alice`), v.Bytes())
+	})
+	t.Run("Unmarshal empty", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.NoError(t, v.UnmarshalText([]byte{}), `UnmarshalText should succeed`)
+		require.Equal(t, 0, v.Len())
+	})
+	t.Run("Unmarshal empty into zero value", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.UnmarshalText([]byte{}), `UnmarshalText should succeed`)
+		require.NotNil(t, v.Bytes())
+		require.Equal(t, 0, v.Len())
+	})
+	t.Run("Unmarshal empty resets decode state", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.UnmarshalText([]byte(`-__-`)), `UnmarshalText should succeed`)
+		require.Equal(t, base64.RawURLEncoding, v.LastDecodeEncoding())
+
+		require.NoError(t, v.UnmarshalText([]byte{}), `UnmarshalText should succeed`)
+		require.Nil(t, v.LastDecodeEncoding(), `encoding of the previous decode should not be reported`)
+	})
+	t.Run("Unmarshal invalid", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.Error(t, v.UnmarshalText([]byte(`!!!`)), `UnmarshalText should fail`)
+	})
+}
+
+func TestSQL(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Source   interface{}
+		Expected []byte
+	}{
+		{Name: "[]byte", Source: []byte(`Alice`), Expected: []byte(`Alice`)},
+		{Name: "string", Source: `Alice`, Expected: []byte(`Alice`)},
+		{Name: "nil", Source: nil, Expected: nil},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New([]byte(`stale`))
+			require.NoError(t, v.Scan(tc.Source), `Scan should succeed`)
+			require.Equal(t, tc.Expected, v.Bytes())
+
+			dv, err := v.Value()
+			require.NoError(t, err, `Value should succeed`)
+			if tc.Expected == nil {
+				require.Nil(t, dv, `Value should be nil`)
+			} else {
+				require.Equal(t, tc.Expected, dv)
+			}
+
+			var rt byteslice.Buffer
+			require.NoError(t, rt.Scan(dv), `Scan should succeed`)
+			require.Equal(t, tc.Expected, rt.Bytes())
+		})
+	}
+	t.Run("invalid", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.Error(t, v.Scan(1), `Scan should fail`)
+	})
+}
+
+func TestString(t *testing.T) {
+	t.Run("populated", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.Equal(t, `QWxpY2U=`, v.String())
+		require.Equal(t, `QWxpY2U=`, fmt.Sprintf("%v", v))
+	})
+	t.Run("empty", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.Equal(t, ``, v.String())
+	})
+	t.Run("nil", func(t *testing.T) {
+		var v *byteslice.Buffer
+		require.Equal(t, ``, v.String())
+	})
+	t.Run("struct field", func(t *testing.T) {
+		type Foo struct {
+			Bar byteslice.Buffer
+		}
+		foo := Foo{Bar: *byteslice.New([]byte(`Alice`))}
+		require.Equal(t, `{QWxpY2U=}`, fmt.Sprint(foo))
+		require.Equal(t, `{QWxpY2U=}`, fmt.Sprintf("%v", foo))
+		require.Equal(t, `{QWxpY2U=}`, fmt.Sprintf("%s", foo))
+	})
+}
+
+func TestHex(t *testing.T) {
+	var v byteslice.Buffer
+	v.SetB64Encoder(byteslice.HexEncoder)
+	v.SetB64Decoder(byteslice.HexDecoder)
+	v.SetBytes([]byte{0xde, 0xad, 0xbe, 0xef})
+
+	buf, err := json.Marshal(v)
+	require.NoError(t, err, `json.Marshal should succeed`)
+	require.Equal(t, []byte(`"deadbeef"`), buf)
+
+	var rt byteslice.Buffer
+	rt.SetB64Decoder(byteslice.HexDecoder)
+	require.NoError(t, json.Unmarshal(buf, &rt), `json.Unmarshal should succeed`)
+	require.Equal(t, v.Bytes(), rt.Bytes())
+}
+
+func TestHexUpper(t *testing.T) {
+	v := byteslice.New([]byte{0xde, 0xad, 0xbe, 0xef})
+	v.SetB64Encoder(byteslice.HexUpperEncoder)
+
+	buf, err := json.Marshal(v)
+	require.NoError(t, err, `json.Marshal should succeed`)
+	require.Equal(t, []byte(`"DEADBEEF"`), buf)
+
+	for _, src := range []string{`DEADBEEF`, `deadbeef`, `DeAdBeEf`} {
+		var rt byteslice.Buffer
+		rt.SetB64Decoder(byteslice.HexUpperDecoder)
+		require.NoError(t, rt.DecodeString(src), `DecodeString should succeed for %s`, src)
+		require.Equal(t, v.Bytes(), rt.Bytes())
+	}
+
+	_, err = byteslice.HexUpperDecoder.DecodeString(`DEADBEEG`)
+	require.Error(t, err, `DecodeString should fail`)
+}
+
+func TestBase32(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Encoder  byteslice.B64Encoder
+		Decoder  byteslice.B64Decoder
+		Payload  string
+		Expected string
+	}{
+		{Name: "Std", Encoder: byteslice.Base32StdEncoder, Decoder: byteslice.Base32StdDecoder, Payload: `Alice`, Expected: `IFWGSY3F`},
+		{Name: "Std (padded)", Encoder: byteslice.Base32StdEncoder, Decoder: byteslice.Base32StdDecoder, Payload: `Alice!`, Expected: `IFWGSY3FEE======`},
+		{Name: "Hex", Encoder: byteslice.Base32HexEncoder, Decoder: byteslice.Base32HexDecoder, Payload: `Alice`, Expected: `85M6IOR5`},
+		{Name: "Hex (padded)", Encoder: byteslice.Base32HexEncoder, Decoder: byteslice.Base32HexDecoder, Payload: `Alice!`, Expected: `85M6IOR544======`},
+		{Name: "RawStd", Encoder: byteslice.Base32RawStdEncoder, Decoder: byteslice.Base32RawStdDecoder, Payload: `Alice!`, Expected: `IFWGSY3FEE`},
+		{Name: "RawHex", Encoder: byteslice.Base32RawHexEncoder, Decoder: byteslice.Base32RawHexDecoder, Payload: `Alice!`, Expected: `85M6IOR544`},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New([]byte(tc.Payload))
+			v.SetB64Encoder(tc.Encoder).SetB64Decoder(tc.Decoder)
+
+			buf, err := json.Marshal(v)
+			require.NoError(t, err, `json.Marshal should succeed`)
+			require.Equal(t, strconv.Quote(tc.Expected), string(buf))
+
+			var rt byteslice.Buffer
+			rt.SetB64Decoder(tc.Decoder)
+			require.NoError(t, json.Unmarshal(buf, &rt), `json.Unmarshal should succeed`)
+			require.Equal(t, []byte(tc.Payload), rt.Bytes())
+		})
+	}
+}
+
+func TestNewFromString(t *testing.T) {
+	testcases := []struct {
+		Name    string
+		Payload string
+	}{
+		{Name: "padded", Payload: `+/8/+w==`},
+		{Name: "unpadded", Payload: `+/8/+w`},
+		{Name: "URL-safe", Payload: `-_8_-w`},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := byteslice.NewFromString(tc.Payload)
+			require.NoError(t, err, `NewFromString should succeed`)
+			require.Equal(t, []byte{0xfb, 0xff, 0x3f, 0xfb}, v.Bytes())
+		})
+	}
+	t.Run("explicit decoder", func(t *testing.T) {
+		v, err := byteslice.NewFromString(`deadbeef`, byteslice.HexDecoder)
+		require.NoError(t, err, `NewFromString should succeed`)
+		require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, v.Bytes())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := byteslice.NewFromString(`!!!`)
+		require.Error(t, err, `NewFromString should fail`)
+	})
+}
+
+func TestConcat(t *testing.T) {
+	a := byteslice.New([]byte(`Alice`))
+	b := byteslice.New([]byte{})
+	c := byteslice.New([]byte(` and Bob`), byteslice.WithB64Encoder(byteslice.HexEncoder))
+
+	v := byteslice.Concat(a, nil, b, c)
+	require.Equal(t, []byte(`Alice and Bob`), v.Bytes())
+	require.Equal(t, v.Len(), v.Cap(), `the result should be preallocated to the exact length`)
+	require.Equal(t, byteslice.GlobalB64Encoder(), v.B64Encoder(), `configuration should not be carried over`)
+
+	v.Bytes()[0] = 'a'
+	require.Equal(t, []byte(`Alice`), a.Bytes(), `the result should not share storage with the inputs`)
+
+	require.Equal(t, 0, byteslice.Concat().Len())
+	require.Equal(t, 0, byteslice.Concat(nil, nil).Len())
+}
+
+func TestClone(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+	v.SetB64Encoder(base64.RawURLEncoding)
+
+	clone := v.Clone()
+	require.Equal(t, v.Bytes(), clone.Bytes())
+	require.Equal(t, v.B64Encoder(), clone.B64Encoder(), `encoder should be carried over`)
+
+	v.SetBytes([]byte(`Bob`))
+	require.Equal(t, []byte(`Alice`), clone.Bytes(), `clone should not be affected by SetBytes`)
+
+	v.Bytes()[0] = 'b'
+	require.Equal(t, []byte(`Alice`), clone.Bytes(), `clone should not share the backing array`)
+
+	var nilbuf *byteslice.Buffer
+	require.Nil(t, nilbuf.Clone())
+}
+
+func TestEqual(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Left     *byteslice.Buffer
+		Right    *byteslice.Buffer
+		Expected bool
+	}{
+		{Name: "equal", Left: byteslice.New([]byte(`Alice`)), Right: byteslice.New([]byte(`Alice`)), Expected: true},
+		{Name: "unequal", Left: byteslice.New([]byte(`Alice`)), Right: byteslice.New([]byte(`Alica`)), Expected: false},
+		{Name: "different length", Left: byteslice.New([]byte(`Alice`)), Right: byteslice.New([]byte(`Alic`)), Expected: false},
+		{Name: "nil receiver", Left: nil, Right: byteslice.New([]byte(`Alice`)), Expected: false},
+		{Name: "nil argument", Left: byteslice.New([]byte(`Alice`)), Right: nil, Expected: false},
+		{Name: "both nil", Left: nil, Right: nil, Expected: true},
+		{Name: "nil and empty", Left: nil, Right: byteslice.New([]byte{}), Expected: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, tc.Left.Equal(tc.Right), `Equal should return %t`, tc.Expected)
+			require.Equal(t, tc.Expected, tc.Left.EqualConstantTime(tc.Right), `EqualConstantTime should return %t`, tc.Expected)
+		})
+	}
+}
+
+func TestEqualBytesAndString(t *testing.T) {
+	v := byteslice.New([]byte{0xfb, 0xff, 0xfe})
+	t.Run("EqualBytes", func(t *testing.T) {
+		require.True(t, v.EqualBytes([]byte{0xfb, 0xff, 0xfe}))
+		require.False(t, v.EqualBytes([]byte{0xfb, 0xff}))
+		require.False(t, v.EqualBytes(nil))
+
+		var nilbuf *byteslice.Buffer
+		require.True(t, nilbuf.EqualBytes([]byte{}))
+	})
+	t.Run("EqualString", func(t *testing.T) {
+		// the same bytes, in several encodings accepted by the heuristic decoder
+		for _, src := range []string{`+//+`, `-__-`} {
+			require.True(t, v.EqualString(src), `EqualString(%q) should be true`, src)
+		}
+		require.False(t, v.EqualString(`+//+QQ==`), `different bytes`)
+		require.False(t, v.EqualString(`!!!!`), `invalid input`)
+
+		hex := byteslice.New([]byte{0xfb, 0xff, 0xfe}, byteslice.WithB64Decoder(byteslice.HexDecoder))
+		require.True(t, hex.EqualString(`FBFFFE`), `should use the instance decoder`)
+		require.False(t, hex.EqualString(`+//+`), `should use the instance decoder`)
+
+		var nilbuf *byteslice.Buffer
+		require.True(t, nilbuf.EqualString(``))
+	})
+}
+
+func TestWipe(t *testing.T) {
+	v := byteslice.New([]byte(`secret key material`))
+	backing := v.Bytes()
+
+	v.Wipe()
+	require.Nil(t, v.Bytes(), `Bytes should return nil after Wipe`)
+	require.Equal(t, make([]byte, len(backing)), backing, `backing array should be zeroed`)
+
+	require.NotPanics(t, v.Wipe, `Wipe should be safe to call twice`)
+
+	var nilbuf *byteslice.Buffer
+	require.NotPanics(t, nilbuf.Wipe, `Wipe should be safe on a nil Buffer`)
+}
+
+func TestClear(t *testing.T) {
+	v := byteslice.New([]byte(`stale contents`))
+	v.SetB64Encoder(base64.RawURLEncoding)
+	v.Truncate(5)
+	before := v.Cap()
+	backing := v.Bytes()[:before]
+
+	v.Clear()
+	require.Equal(t, 0, v.Len(), `length should be zero`)
+	require.NotNil(t, v.Bytes(), `Bytes should not be nil`)
+	require.Equal(t, before, v.Cap(), `capacity should be retained`)
+	require.Equal(t, make([]byte, before), backing, `backing array should be zeroed, including past the length`)
+	require.Equal(t, base64.RawURLEncoding, v.B64Encoder(), `configuration should be kept`)
+
+	v.Append('A')
+	require.Equal(t, []byte(`A`), v.Bytes())
+
+	var nilbuf *byteslice.Buffer
+	require.NotPanics(t, nilbuf.Clear, `Clear should be safe on a nil Buffer`)
+}
+
+func TestWrite(t *testing.T) {
+	var v byteslice.Buffer
+	for _, chunk := range []string{`Alice`, ` and `, `Bob`} {
+		n, err := v.Write([]byte(chunk))
+		require.NoError(t, err, `Write should succeed`)
+		require.Equal(t, len(chunk), n)
+	}
+	fmt.Fprintf(&v, ` (%d)`, 2)
+	require.Equal(t, []byte(`Alice and Bob (2)`), v.Bytes())
+}
+
+func TestReader(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+	r := v.Reader()
+	v.SetBytes([]byte(`Bob`))
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err, `io.ReadAll should succeed`)
+	require.Equal(t, []byte(`Alice`), data, `reader should see a snapshot`)
+
+	data, err = io.ReadAll(v.Reader())
+	require.NoError(t, err, `io.ReadAll should succeed`)
+	require.Equal(t, v.Bytes(), data)
+}
+
+func TestAppend(t *testing.T) {
+	var v byteslice.Buffer
+	v.Append('A', 'l').AppendString(`ic`).Append()
+	v.Append([]byte(`e`)...)
+	require.Equal(t, []byte(`Alice`), v.Bytes())
+	require.Equal(t, 5, v.Len())
+}
+
+func TestPrepend(t *testing.T) {
+	t.Run("empty buffer", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.Prepend('A', 'l')
+		require.Equal(t, []byte(`Al`), v.Bytes())
+	})
+	t.Run("non-empty buffer", func(t *testing.T) {
+		v := byteslice.New([]byte(`payload`))
+		v.Prepend(0x07).Prepend(0x01, 0x02)
+		require.Equal(t, []byte{0x01, 0x02, 0x07, 'p', 'a', 'y', 'l', 'o', 'a', 'd'}, v.Bytes())
+	})
+	t.Run("nothing", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.Prepend()
+		require.Equal(t, []byte(`Alice`), v.Bytes())
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	t.Run("shorter", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.Truncate(3)
+		require.Equal(t, []byte(`Ali`), v.Bytes())
+
+		v.Append('x')
+		require.Equal(t, []byte(`Alix`), v.Bytes())
+	})
+	t.Run("zero", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.Truncate(0)
+		require.Equal(t, 0, v.Len())
+	})
+	t.Run("out of range", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.Panics(t, func() { v.Truncate(6) }, `Truncate should panic`)
+		require.Panics(t, func() { v.Truncate(-1) }, `Truncate should panic`)
+	})
+}
+
+func TestInsertAt(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Pos      int
+		Expected string
+	}{
+		{Name: "start", Pos: 0, Expected: `--Alice`},
+		{Name: "middle", Pos: 2, Expected: `Al--ice`},
+		{Name: "end", Pos: 5, Expected: `Alice--`},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New([]byte(`Alice`))
+			require.NoError(t, v.InsertAt(tc.Pos, []byte(`--`)), `InsertAt should succeed`)
+			require.Equal(t, tc.Expected, string(v.Bytes()))
+
+			// same, but without reallocation
+			v = byteslice.New([]byte(`Alice`))
+			v.Grow(2)
+			require.NoError(t, v.InsertAt(tc.Pos, []byte(`--`)), `InsertAt should succeed`)
+			require.Equal(t, tc.Expected, string(v.Bytes()))
+		})
+	}
+	t.Run("empty buffer", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.InsertAt(0, []byte(`Alice`)), `InsertAt should succeed`)
+		require.Equal(t, `Alice`, string(v.Bytes()))
+	})
+	t.Run("out of range", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.Error(t, v.InsertAt(-1, []byte(`-`)), `InsertAt should fail`)
+		require.Error(t, v.InsertAt(6, []byte(`-`)), `InsertAt should fail`)
+		require.Equal(t, `Alice`, string(v.Bytes()), `contents should be left untouched`)
+	})
+}
+
+func TestDeleteRange(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Low      int
+		High     int
+		Expected string
+		Error    bool
+	}{
+		{Name: "empty range", Low: 2, High: 2, Expected: `Alice`},
+		{Name: "full range", Low: 0, High: 5, Expected: ``},
+		{Name: "partial range at start", Low: 0, High: 2, Expected: `ice`},
+		{Name: "partial range in middle", Low: 1, High: 4, Expected: `Ae`},
+		{Name: "partial range at end", Low: 3, High: 5, Expected: `Ali`},
+		{Name: "negative low", Low: -1, High: 2, Expected: `Alice`, Error: true},
+		{Name: "high before low", Low: 3, High: 2, Expected: `Alice`, Error: true},
+		{Name: "high out of range", Low: 0, High: 6, Expected: `Alice`, Error: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New([]byte(`Alice`))
+			err := v.DeleteRange(tc.Low, tc.High)
+			if tc.Error {
+				require.Error(t, err, `DeleteRange should fail`)
+			} else {
+				require.NoError(t, err, `DeleteRange should succeed`)
+			}
+			require.Equal(t, tc.Expected, string(v.Bytes()))
+		})
+	}
+}
+
+func TestGrow(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+	v.Grow(64)
+	require.Equal(t, []byte(`Alice`), v.Bytes(), `contents should be preserved`)
+
+	before := cap(v.Bytes())
+	require.GreaterOrEqual(t, before, 5+64)
+	for i := 0; i < 64; i++ {
+		v.Append('x')
+	}
+	require.Equal(t, before, cap(v.Bytes()), `Append should not reallocate after Grow`)
+	require.Panics(t, func() { v.Grow(-1) }, `Grow should panic`)
+}
+
+func TestCap(t *testing.T) {
+	var nilbuf *byteslice.Buffer
+	require.Equal(t, 0, nilbuf.Cap())
+
+	var v byteslice.Buffer
+	require.Equal(t, 0, v.Cap())
+
+	v.Grow(64)
+	require.GreaterOrEqual(t, v.Cap(), 64, `Cap should grow after Grow`)
+
+	before := v.Cap()
+	v.Append(bytes.Repeat([]byte{'x'}, before+1)...)
+	require.Greater(t, v.Cap(), before, `Cap should grow after Append`)
+	require.Equal(t, cap(v.Bytes()), v.Cap())
+}
+
+func BenchmarkAppend(b *testing.B) {
+	chunk := []byte(`0123456789abcdef`)
+	b.Run("without Grow", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v byteslice.Buffer
+			for j := 0; j < 64; j++ {
+				v.Append(chunk...)
+			}
+		}
+	})
+	b.Run("with Grow", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v byteslice.Buffer
+			v.Grow(64 * len(chunk))
+			for j := 0; j < 64; j++ {
+				v.Append(chunk...)
+			}
+		}
+	})
+}
+
+// BenchmarkAppendLocking shows the cost of synchronization. Buffer itself
+// does not lock, so goroutine-local buffers pay nothing, and only shared
+// buffers need to be guarded by the caller.
+func BenchmarkAppendLocking(b *testing.B) {
+	chunk := []byte(`0123456789abcdef`)
+	b.Run("unsynchronized", func(b *testing.B) {
+		var v byteslice.Buffer
+		for i := 0; i < b.N; i++ {
+			v.Append(chunk...)
+			if v.Len() > 1<<16 {
+				v.Reset()
+			}
+		}
+	})
+	b.Run("guarded by caller", func(b *testing.B) {
+		var mu sync.RWMutex
+		var v byteslice.Buffer
+		for i := 0; i < b.N; i++ {
+			mu.Lock()
+			v.Append(chunk...)
+			if v.Len() > 1<<16 {
+				v.Reset()
+			}
+			mu.Unlock()
+		}
+	})
+}
+
+func TestNilAsNull(t *testing.T) {
+	testcases := []struct {
+		Name      string
+		NilAsNull bool
+		Data      []byte
+		Expected  string
+	}{
+		{Name: "default, nil", Data: nil, Expected: `""`},
+		{Name: "default, empty", Data: []byte{}, Expected: `""`},
+		{Name: "nil as null, nil", NilAsNull: true, Data: nil, Expected: `null`},
+		{Name: "nil as null, empty", NilAsNull: true, Data: []byte{}, Expected: `""`},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			var v byteslice.Buffer
+			v.SetNilAsNull(tc.NilAsNull)
+			if tc.Data != nil {
+				v.SetBytes(tc.Data)
+			}
+
+			buf, err := json.Marshal(v)
+			require.NoError(t, err, `json.Marshal should succeed`)
+			require.Equal(t, tc.Expected, string(buf))
+		})
+	}
+	t.Run("unmarshal null", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.SetNilAsNull(true)
+		require.NoError(t, json.Unmarshal([]byte(`null`), v), `json.Unmarshal should succeed`)
+		require.Nil(t, v.Bytes(), `Bytes should be nil`)
+	})
+}
+
+func TestUnmarshalNull(t *testing.T) {
+	var foo struct {
+		Bar byteslice.Buffer `json:"bar"`
+	}
+	foo.Bar.SetBytes([]byte(`stale`))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"bar":null}`), &foo), `json.Unmarshal should succeed`)
+	require.Nil(t, foo.Bar.Bytes(), `Bytes should be nil`)
+	require.Equal(t, 0, foo.Bar.Len())
+}
+
+func TestUnmarshalJSONArray(t *testing.T) {
+	var foo struct {
+		Bar byteslice.Buffer `json:"bar"`
+	}
+	t.Run("array", func(t *testing.T) {
+		require.NoError(t, json.Unmarshal([]byte(`{"bar":[72, 105, 0, 255]}`), &foo), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte{'H', 'i', 0, 255}, foo.Bar.Bytes())
+	})
+	t.Run("empty array", func(t *testing.T) {
+		require.NoError(t, json.Unmarshal([]byte(`{"bar":[]}`), &foo), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte{}, foo.Bar.Bytes())
+	})
+	t.Run("string", func(t *testing.T) {
+		require.NoError(t, json.Unmarshal([]byte(`{"bar":"SGk="}`), &foo), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte(`Hi`), foo.Bar.Bytes())
+	})
+	t.Run("out of range", func(t *testing.T) {
+		foo.Bar.SetBytes([]byte(`stale`))
+		for _, src := range []string{`[72,256]`, `[-1]`} {
+			err := json.Unmarshal([]byte(`{"bar":`+src+`}`), &foo)
+			require.Error(t, err, `json.Unmarshal should fail for %s`, src)
+			require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+		}
+		require.Equal(t, []byte(`stale`), foo.Bar.Bytes(), `contents should be left untouched`)
+	})
+	t.Run("not an integer", func(t *testing.T) {
+		require.Error(t, json.Unmarshal([]byte(`{"bar":[1.5]}`), &foo), `json.Unmarshal should fail`)
+		require.Error(t, json.Unmarshal([]byte(`{"bar":["a"]}`), &foo), `json.Unmarshal should fail`)
+	})
+}
+
+func TestJSONArrayMode(t *testing.T) {
+	type foo struct {
+		Bar byteslice.Buffer `json:"bar"`
+	}
+	t.Run("array mode", func(t *testing.T) {
+		var src foo
+		src.Bar.SetJSONArrayMode(true)
+		src.Bar.SetBytes([]byte{'H', 'i', 0, 255})
+
+		buf, err := json.Marshal(src)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `{"bar":[72,105,0,255]}`, string(buf))
+
+		var dst foo
+		require.NoError(t, json.Unmarshal(buf, &dst), `json.Unmarshal should succeed`)
+		require.Equal(t, src.Bar.Bytes(), dst.Bar.Bytes())
+	})
+	t.Run("empty", func(t *testing.T) {
+		var src foo
+		src.Bar.SetJSONArrayMode(true)
+		buf, err := json.Marshal(src)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `{"bar":[]}`, string(buf))
+
+		src.Bar.SetNilAsNull(true)
+		buf, err = json.Marshal(src)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `{"bar":null}`, string(buf))
+	})
+	t.Run("string mode", func(t *testing.T) {
+		var src foo
+		src.Bar.SetBytes([]byte(`Hi`))
+		src.Bar.SetJSONArrayMode(true)
+		src.Bar.SetJSONArrayMode(false)
+
+		buf, err := json.Marshal(src)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `{"bar":"SGk="}`, string(buf))
+	})
+}
+
+type mixedEncodings struct {
+	Hex byteslice.Buffer `json:"hex"`
+	URL byteslice.Buffer `json:"url"`
+}
+
+func (m *mixedEncodings) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Hex json.RawMessage `json:"hex"`
+		URL json.RawMessage `json:"url"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := m.Hex.UnmarshalJSONWith(raw.Hex, byteslice.HexDecoder); err != nil {
+		return err
+	}
+	return m.URL.UnmarshalJSONWith(raw.URL, base64.RawURLEncoding)
+}
+
+func TestUnmarshalJSONWith(t *testing.T) {
+	t.Run("two fields", func(t *testing.T) {
+		var m mixedEncodings
+		require.NoError(t, json.Unmarshal([]byte(`{"hex":"fbff","url":"-__-"}`), &m), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte{0xfb, 0xff}, m.Hex.Bytes())
+		require.Equal(t, []byte{0xfb, 0xff, 0xfe}, m.URL.Bytes())
+		require.Equal(t, byteslice.GlobalB64Decoder(), m.Hex.B64Decoder(), `decoder association should not change`)
+
+		require.Error(t, json.Unmarshal([]byte(`{"hex":"-__-","url":"-__-"}`), &m), `json.Unmarshal should fail`)
+	})
+	t.Run("nil decoder", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Decoder(byteslice.HexDecoder)
+		require.NoError(t, v.UnmarshalJSONWith([]byte(`"fbff"`), nil), `UnmarshalJSONWith should succeed`)
+		require.Equal(t, []byte{0xfb, 0xff}, v.Bytes())
+	})
+	t.Run("null and arrays", func(t *testing.T) {
+		v := byteslice.New([]byte(`stale`))
+		require.NoError(t, v.UnmarshalJSONWith([]byte(`[1,2]`), byteslice.HexDecoder), `UnmarshalJSONWith should succeed`)
+		require.Equal(t, []byte{1, 2}, v.Bytes())
+		require.NoError(t, v.UnmarshalJSONWith([]byte(`null`), byteslice.HexDecoder), `UnmarshalJSONWith should succeed`)
+		require.Nil(t, v.Bytes())
+	})
+}
+
+func TestUnmarshalJSONFastPath(t *testing.T) {
+	payload := []byte{0xfb, 0xff, 0xfe, 'A', 'l', 'i', 'c', 'e'}
+	testcases := []struct {
+		Name    string
+		Source  string
+		Decoder byteslice.B64Decoder
+		Error   bool
+	}{
+		{Name: "heuristic", Source: `"+//+QWxpY2U="`},
+		{Name: "heuristic raw URL", Source: `"-__-QWxpY2U"`},
+		{Name: "heuristic with surrounding spaces", Source: `" +//+QWxpY2U= "`},
+		{Name: "encoding", Source: `"-__-QWxpY2U"`, Decoder: base64.RawURLEncoding},
+		{Name: "escaped characters", Source: `"\u002b\/\/+QWxpY2U="`},
+		{Name: "escaped newline", Source: `"+//+\nQWxpY2U="`, Decoder: base64.StdEncoding},
+		{Name: "invalid", Source: `"+//+QWxpY2U!"`, Error: true},
+		{Name: "invalid for encoding", Source: `"+//+QWxpY2U="`, Decoder: base64.RawURLEncoding, Error: true},
+		{Name: "only padding", Source: `"===="`, Error: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New([]byte(`stale`))
+			v.SetB64Decoder(tc.Decoder)
+			err := json.Unmarshal([]byte(tc.Source), v)
+			if tc.Error {
+				require.Error(t, err, `json.Unmarshal should fail`)
+				require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+				require.Equal(t, []byte(`stale`), v.Bytes(), `contents should be left untouched`)
+				return
+			}
+			require.NoError(t, err, `json.Unmarshal should succeed`)
+			require.Equal(t, payload, v.Bytes())
+		})
+	}
+	t.Run("empty string", func(t *testing.T) {
+		for _, dec := range []byteslice.B64Decoder{nil, base64.StdEncoding} {
+			var v byteslice.Buffer
+			v.SetB64Decoder(dec)
+			require.NoError(t, json.Unmarshal([]byte(`""`), &v), `json.Unmarshal should succeed`)
+			require.NotNil(t, v.Bytes())
+			require.Equal(t, 0, v.Len())
+		}
+	})
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	payload := make([]byte, 1<<16)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	data, _ := json.Marshal(base64.StdEncoding.EncodeToString(payload))
+
+	b.Run("fast path", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v byteslice.Buffer
+			_ = v.UnmarshalJSON(data)
+		}
+	})
+	b.Run("fast path with base64.Encoding", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v byteslice.Buffer
+			v.SetB64Decoder(base64.StdEncoding)
+			_ = v.UnmarshalJSON(data)
+		}
+	})
+	b.Run("fast path with maximum length", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v byteslice.Buffer
+			v.SetMaxDecodeLen(len(payload))
+			_ = v.UnmarshalJSON(data)
+		}
+	})
+	b.Run("regular path", func(b *testing.B) {
+		// a B64DecoderFunc is not eligible for the fast path
+		dec := byteslice.B64DecoderFunc(byteslice.HeuristicB64Decoder.DecodeString)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var v byteslice.Buffer
+			v.SetB64Decoder(dec)
+			_ = v.UnmarshalJSON(data)
+		}
+	})
+}
+
+func TestRoundTripEncoding(t *testing.T) {
+	encoders := map[string]*base64.Encoding{
+		"RawURL": base64.RawURLEncoding,
+		"RawStd": base64.RawStdEncoding,
+		"URL":    base64.URLEncoding,
+		"Std":    base64.StdEncoding,
+	}
+	message := []byte{0xfb, 0xff, 0xfe, 0x00}
+
+	for name, enc := range encoders {
+		src := strconv.Quote(enc.EncodeToString(message))
+		t.Run(name, func(t *testing.T) {
+			var v byteslice.Buffer
+			v.SetRoundTripEncoding(true)
+			require.NoError(t, json.Unmarshal([]byte(src), &v), `json.Unmarshal should succeed`)
+			require.Equal(t, message, v.Bytes())
+
+			buf, err := json.Marshal(v)
+			require.NoError(t, err, `json.Marshal should succeed`)
+			require.Equal(t, src, string(buf), `re-marshaled value should use the same encoding`)
+		})
+	}
+	t.Run("disabled", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, json.Unmarshal([]byte(`"-__-AA"`), &v), `json.Unmarshal should succeed`)
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"+//+AA=="`, string(buf), `re-marshaled value should use the default encoding`)
+	})
+}
+
+func TestLastDecodeEncoding(t *testing.T) {
+	payload := []byte{0xfb, 0xff, 0xfe, 'A'}
+	testcases := []*base64.Encoding{
+		base64.StdEncoding,
+		base64.URLEncoding,
+		base64.RawStdEncoding,
+		base64.RawURLEncoding,
+	}
+	for _, enc := range testcases {
+		enc := enc
+		encoded := enc.EncodeToString(payload)
+		t.Run(encoded, func(t *testing.T) {
+			var v byteslice.Buffer
+			require.Nil(t, v.LastDecodeEncoding(), `nothing has been decoded yet`)
+			require.NoError(t, v.DecodeString(encoded), `DecodeString should succeed`)
+			require.Equal(t, payload, v.Bytes())
+			require.Equal(t, enc, v.LastDecodeEncoding())
+		})
+	}
+	t.Run("unknown", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.DecodeString(`QWxpY2U=`), `DecodeString should succeed`)
+		require.NotNil(t, v.LastDecodeEncoding())
+
+		v.SetB64Decoder(byteslice.HexDecoder)
+		require.NoError(t, v.DecodeString(`416c696365`), `DecodeString should succeed`)
+		require.Nil(t, v.LastDecodeEncoding(), `hex decoder does not report an encoding`)
+	})
+	t.Run("nil receiver", func(t *testing.T) {
+		var v *byteslice.Buffer
+		require.Nil(t, v.LastDecodeEncoding())
+	})
+}
+
+func BenchmarkAppendText(b *testing.B) {
+	payload := make([]byte, 1024)
+	v := byteslice.New(payload)
+
+	b.Run("MarshalText", func(b *testing.B) {
+		var dst []byte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			text, _ := v.MarshalText()
+			dst = append(dst[:0], text...)
+		}
+	})
+	b.Run("AppendText", func(b *testing.B) {
+		var dst []byte
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dst, _ = v.AppendText(dst[:0])
+		}
+	})
+}
+
+func TestHeuristicB64Decoder(t *testing.T) {
+	defer byteslice.SetGlobalB64Decoder(byteslice.HeuristicB64Decoder)
+
+	byteslice.SetGlobalB64Decoder(base64.StdEncoding)
+	var v byteslice.Buffer
+	require.Error(t, v.AcceptValue(`-__-AA`), `StdEncoding should not decode RawURL strings`)
+
+	byteslice.SetGlobalB64Decoder(byteslice.HeuristicB64Decoder)
+	for _, src := range []string{`-__-AA`, `-__-AA==`, `+//+AA`, `+//+AA==`} {
+		require.NoError(t, v.AcceptValue(src), `heuristic decoder should decode %q`, src)
+		require.Equal(t, []byte{0xfb, 0xff, 0xfe, 0x00}, v.Bytes())
+	}
+}
+
+func TestHeuristicDecoderEdgeCases(t *testing.T) {
+	dec := byteslice.NewHeuristicDecoder()
+	t.Run("empty", func(t *testing.T) {
+		buf, err := dec.DecodeString(``)
+		require.NoError(t, err, `DecodeString should succeed`)
+		require.Equal(t, []byte{}, buf)
+	})
+	t.Run("whitespace only", func(t *testing.T) {
+		buf, err := dec.DecodeString(" \t\r\n")
+		require.NoError(t, err, `DecodeString should succeed`)
+		require.Equal(t, []byte{}, buf)
+	})
+	t.Run("whitespace padded", func(t *testing.T) {
+		buf, err := dec.DecodeString(" QWxpY2U=\n")
+		require.NoError(t, err, `DecodeString should succeed`)
+		require.Equal(t, []byte(`Alice`), buf)
+	})
+	t.Run("lone padding", func(t *testing.T) {
+		_, err := dec.DecodeString(`=`)
+		require.Error(t, err, `DecodeString should fail`)
+		require.Contains(t, err.Error(), `padding`)
+	})
+}
+
+func TestStrictDecoder(t *testing.T) {
+	t.Run("alphabet collision", func(t *testing.T) {
+		// Meant to be StdEncoding, but contains URL-safe characters
+		const src = `-__-AA==`
+
+		_, err := byteslice.HeuristicB64Decoder.DecodeString(src)
+		require.NoError(t, err, `heuristic decoder accepts URL-safe characters`)
+
+		_, err = byteslice.NewStrictDecoder(base64.StdEncoding).DecodeString(src)
+		require.Error(t, err, `strict decoder should reject URL-safe characters`)
+	})
+	t.Run("shared alphabet", func(t *testing.T) {
+		// StdEncoding, but does not contain '+' or '/'
+		const src = `QWxpY2U=`
+
+		buf, err := byteslice.NewStrictDecoder(base64.StdEncoding).DecodeString(src)
+		require.NoError(t, err, `strict decoder should succeed`)
+		require.Equal(t, []byte(`Alice`), buf)
+	})
+	t.Run("non-zero padding bits", func(t *testing.T) {
+		const src = `QWxpY2V=`
+
+		buf, err := byteslice.HeuristicB64Decoder.DecodeString(src)
+		require.NoError(t, err, `heuristic decoder ignores padding bits`)
+		require.Equal(t, []byte(`Alice`), buf)
+
+		_, err = byteslice.NewStrictDecoder(base64.StdEncoding).DecodeString(src)
+		require.Error(t, err, `strict decoder should reject non-zero padding bits`)
+	})
+}
+
+func TestMultiSegmentDecoder(t *testing.T) {
+	dec := byteslice.NewMultiSegmentDecoder(base64.StdEncoding)
+	t.Run("two segments", func(t *testing.T) {
+		// "QWxpY2U=" + "Qm9i"
+		const src = `QWxpY2U=Qm9i`
+		_, err := base64.StdEncoding.DecodeString(src)
+		require.Error(t, err, `StdEncoding should reject concatenated segments`)
+
+		buf, err := dec.DecodeString(src)
+		require.NoError(t, err, `DecodeString should succeed`)
+		require.Equal(t, []byte(`AliceBob`), buf)
+	})
+	t.Run("three segments", func(t *testing.T) {
+		src := base64.StdEncoding.EncodeToString([]byte(`A`)) +
+			base64.StdEncoding.EncodeToString([]byte(`Bo`)) +
+			base64.StdEncoding.EncodeToString([]byte(`Carol`))
+		require.Equal(t, `QQ==Qm8=Q2Fyb2w=`, src)
+
+		var v byteslice.Buffer
+		v.SetB64Decoder(dec)
+		require.NoError(t, v.DecodeString(src), `DecodeString should succeed`)
+		require.Equal(t, []byte(`ABoCarol`), v.Bytes())
+	})
+	t.Run("single segment", func(t *testing.T) {
+		buf, err := dec.DecodeString(`QWxpY2U=`)
+		require.NoError(t, err, `DecodeString should succeed`)
+		require.Equal(t, []byte(`Alice`), buf)
+	})
+	t.Run("invalid segment", func(t *testing.T) {
+		_, err := dec.DecodeString(`QQ==Q===`)
+		require.Error(t, err, `DecodeString should fail`)
+	})
+	t.Run("no padding", func(t *testing.T) {
+		buf, err := byteslice.NewMultiSegmentDecoder(base64.RawStdEncoding).DecodeString(`QWxpY2U`)
+		require.NoError(t, err, `DecodeString should succeed`)
+		require.Equal(t, []byte(`Alice`), buf)
+	})
+}
+
+func TestAppendEncoded(t *testing.T) {
+	t.Run("base64.Encoding", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.Equal(t, []byte(`data=QWxpY2U=`), v.AppendEncoded([]byte(`data=`)))
+
+		dst := make([]byte, 0, 64)
+		require.Equal(t, []byte(`QWxpY2U=`), v.AppendEncoded(dst))
+	})
+	t.Run("custom encoder", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.SetB64Encoder(byteslice.HexEncoder)
+		require.Equal(t, []byte(`data=416c696365`), v.AppendEncoded([]byte(`data=`)))
+	})
+	t.Run("nil", func(t *testing.T) {
+		var v *byteslice.Buffer
+		require.Equal(t, []byte(`data=`), v.AppendEncoded([]byte(`data=`)))
+	})
+}
+
+func BenchmarkAppendEncoded(b *testing.B) {
+	v := byteslice.New(make([]byte, 1024))
+	dst := make([]byte, 0, 2048)
+	b.Run("AppendEncoded", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = v.AppendEncoded(dst[:0])
+		}
+	})
+	b.Run("EncodeToString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = append(dst[:0], []byte(v.B64Encoder().EncodeToString(v.Bytes()))...)
+		}
+	})
+}
+
+type appendEncoder struct {
+	appendCalls int
+}
+
+func (e *appendEncoder) EncodeToString(src []byte) string {
+	return base64.StdEncoding.EncodeToString(src)
+}
+
+func (e *appendEncoder) AppendEncode(dst, src []byte) []byte {
+	e.appendCalls++
+	return append(dst, base64.StdEncoding.EncodeToString(src)...)
+}
+
+func TestAppendEncoder(t *testing.T) {
+	t.Run("EncodeToString only", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.SetB64Encoder(byteslice.B64EncoderFunc(base64.StdEncoding.EncodeToString))
+
+		text, err := v.MarshalText()
+		require.NoError(t, err, `MarshalText should succeed`)
+		require.Equal(t, []byte(`QWxpY2U=`), text)
+	})
+	t.Run("AppendEncode", func(t *testing.T) {
+		var enc appendEncoder
+		v := byteslice.New([]byte(`Alice`))
+		v.SetB64Encoder(&enc)
+
+		text, err := v.MarshalText()
+		require.NoError(t, err, `MarshalText should succeed`)
+		require.Equal(t, []byte(`QWxpY2U=`), text)
+		require.Equal(t, []byte(`x=QWxpY2U=`), v.AppendEncoded([]byte(`x=`)))
+		require.Equal(t, 2, enc.appendCalls, `AppendEncode should be used`)
+	})
+}
+
+func TestCompare(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Left     *byteslice.Buffer
+		Right    *byteslice.Buffer
+		Expected int
+	}{
+		{Name: "less", Left: byteslice.New([]byte(`Alice`)), Right: byteslice.New([]byte(`Bob`)), Expected: -1},
+		{Name: "equal", Left: byteslice.New([]byte(`Alice`)), Right: byteslice.New([]byte(`Alice`)), Expected: 0},
+		{Name: "greater", Left: byteslice.New([]byte(`Bob`)), Right: byteslice.New([]byte(`Alice`)), Expected: 1},
+		{Name: "prefix", Left: byteslice.New([]byte(`Ali`)), Right: byteslice.New([]byte(`Alice`)), Expected: -1},
+		{Name: "nil receiver", Left: nil, Right: byteslice.New([]byte(`Alice`)), Expected: -1},
+		{Name: "nil argument", Left: byteslice.New([]byte(`Alice`)), Right: nil, Expected: 1},
+		{Name: "both nil", Left: nil, Right: nil, Expected: 0},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, tc.Left.Compare(tc.Right))
+		})
+	}
+	t.Run("sort", func(t *testing.T) {
+		list := []*byteslice.Buffer{
+			byteslice.New([]byte(`Charlie`)),
+			byteslice.New([]byte(`Alice`)),
+			byteslice.New([]byte(`Bob`)),
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Compare(list[j]) < 0 })
+		require.Equal(t, []byte(`Alice`), list[0].Bytes())
+		require.Equal(t, []byte(`Bob`), list[1].Bytes())
+		require.Equal(t, []byte(`Charlie`), list[2].Bytes())
+	})
+}
+
+func TestBinary(t *testing.T) {
+	type foo struct {
+		Name string
+		Key  byteslice.Buffer
+	}
+
+	var src foo
+	src.Name = `Alice`
+	src.Key.SetBytes([]byte{0x00, 0xde, 0xad, 0xbe, 0xef})
+
+	data, err := src.Key.MarshalBinary()
+	require.NoError(t, err, `MarshalBinary should succeed`)
+	require.Equal(t, src.Key.Bytes(), data, `binary form should be the raw bytes`)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(src), `gob.Encode should succeed`)
+
+	var dst foo
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&dst), `gob.Decode should succeed`)
+	require.Equal(t, src.Name, dst.Name)
+	require.Equal(t, src.Key.Bytes(), dst.Key.Bytes())
+}
+
+func TestNewOptions(t *testing.T) {
+	t.Run("WithB64Encoder", func(t *testing.T) {
+		v := byteslice.New([]byte{0xfb, 0xff, 0xfe}, byteslice.WithB64Encoder(base64.RawURLEncoding))
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"-__-"`, string(buf))
+	})
+	t.Run("WithB64Decoder", func(t *testing.T) {
+		v := byteslice.New(nil, byteslice.WithB64Decoder(byteslice.HexDecoder))
+		require.NoError(t, json.Unmarshal([]byte(`"deadbeef"`), v), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, v.Bytes())
+	})
+	t.Run("WithRawCopy", func(t *testing.T) {
+		data := []byte(`Alice`)
+		copied := byteslice.New(data)
+		adopted := byteslice.New(data, byteslice.WithRawCopy(false))
+
+		data[0] = 'a'
+		require.Equal(t, []byte(`Alice`), copied.Bytes(), `data should be copied by default`)
+		require.Equal(t, []byte(`alice`), adopted.Bytes(), `data should be adopted with WithRawCopy(false)`)
+	})
+}
+
+func TestAcceptValueTypes(t *testing.T) {
+	t.Run("*[]byte", func(t *testing.T) {
+		data := []byte(`Alice`)
+		var v byteslice.Buffer
+		require.NoError(t, v.AcceptValue(&data), `AcceptValue should succeed`)
+		require.Equal(t, []byte(`Alice`), v.Bytes())
+
+		data[0] = 'a'
+		require.Equal(t, []byte(`Alice`), v.Bytes(), `data should be copied`)
+	})
+	t.Run("json.RawMessage", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.AcceptValue(json.RawMessage(`"QWxpY2U"`)), `AcceptValue should succeed`)
+		require.Equal(t, []byte(`Alice`), v.Bytes())
+
+		require.Error(t, v.AcceptValue(json.RawMessage(`QWxpY2U`)), `AcceptValue should fail for unquoted JSON`)
+	})
+	t.Run("nil", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.NoError(t, v.AcceptValue(nil), `AcceptValue should succeed`)
+		require.Nil(t, v.Bytes())
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.Error(t, v.AcceptValue(1), `AcceptValue should fail`)
+	})
+}
+
+func TestAcceptBuffer(t *testing.T) {
+	src := byteslice.New([]byte{0xfb, 0xff, 0xfe}, byteslice.WithB64Encoder(base64.RawURLEncoding))
+
+	t.Run("without config", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.AcceptBuffer(src, false)
+		require.Equal(t, src.Bytes(), v.Bytes())
+		require.Equal(t, byteslice.GlobalB64Encoder(), v.B64Encoder(), `encoder should not be carried over`)
+	})
+	t.Run("with config", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.AcceptBuffer(src, true)
+		require.Equal(t, src.Bytes(), v.Bytes())
+		require.Equal(t, base64.RawURLEncoding, v.B64Encoder(), `encoder should be carried over`)
+
+		v.Bytes()[0] = 0x00
+		require.Equal(t, byte(0xfb), src.Bytes()[0], `data should be copied`)
+	})
+	t.Run("with config, non-codec settings", func(t *testing.T) {
+		src := byteslice.New([]byte{0xfb, 0xff}).SetJSONArrayMode(true)
+		require.NoError(t, src.DecodeString(`-__-`), `DecodeString should succeed`)
+		require.Equal(t, base64.RawURLEncoding, src.LastDecodeEncoding())
+
+		var v byteslice.Buffer
+		v.AcceptBuffer(src, true)
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `[251,255,254]`, string(buf), `JSON array mode should be carried over`)
+		require.Nil(t, v.LastDecodeEncoding(), `decode state should not be carried over`)
+	})
+}
+
+func TestNewCustomB64(t *testing.T) {
+	const alphabet = `ZYXWVUTSRQPONMLKJIHGFEDCBAzyxwvutsrqponmlkjihgfedcba9876543210-_`
+	t.Run("round trip", func(t *testing.T) {
+		enc, dec, err := byteslice.NewCustomB64(alphabet, '.')
+		require.NoError(t, err, `NewCustomB64 should succeed`)
+
+		v := byteslice.New([]byte(`Alice`), byteslice.WithB64Encoder(enc), byteslice.WithB64Decoder(dec))
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"JDckB7F."`, string(buf))
+
+		rt := byteslice.New(nil, byteslice.WithB64Decoder(dec))
+		require.NoError(t, json.Unmarshal(buf, rt), `json.Unmarshal should succeed`)
+		require.Equal(t, []byte(`Alice`), rt.Bytes())
+	})
+	t.Run("no padding", func(t *testing.T) {
+		enc, _, err := byteslice.NewCustomB64(alphabet, base64.NoPadding)
+		require.NoError(t, err, `NewCustomB64 should succeed`)
+		require.Equal(t, `JDckB7F`, enc.EncodeToString([]byte(`Alice`)))
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := byteslice.NewCustomB64(alphabet[:63], '=')
+		require.Error(t, err, `NewCustomB64 should fail for a 63 byte alphabet`)
+
+		_, _, err = byteslice.NewCustomB64(alphabet[:63]+`Z`, '=')
+		require.Error(t, err, `NewCustomB64 should fail for duplicate symbols`)
+
+		_, _, err = byteslice.NewCustomB64(alphabet, 'Z')
+		require.Error(t, err, `NewCustomB64 should fail for padding in the alphabet`)
+	})
+}
+
+func TestIsNilIsEmpty(t *testing.T) {
+	testcases := []struct {
+		Name    string
+		Buffer  *byteslice.Buffer
+		IsNil   bool
+		IsEmpty bool
+	}{
+		{Name: "nil pointer", Buffer: nil, IsNil: true, IsEmpty: true},
+		{Name: "nil data", Buffer: byteslice.New(nil), IsNil: true, IsEmpty: true},
+		{Name: "empty slice", Buffer: byteslice.New([]byte{}), IsNil: false, IsEmpty: true},
+		{Name: "populated", Buffer: byteslice.New([]byte(`Alice`)), IsNil: false, IsEmpty: false},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.IsNil, tc.Buffer.IsNil(), `IsNil should return %t`, tc.IsNil)
+			require.Equal(t, tc.IsEmpty, tc.Buffer.IsEmpty(), `IsEmpty should return %t`, tc.IsEmpty)
+		})
+	}
+}
+
+func TestHeuristicDecoderLenientPadding(t *testing.T) {
+	// JWT header {"alg":"HS256"}, RawURLEncoding
+	const header = `eyJhbGciOiJIUzI1NiJ9`
+	payload := []byte{0xfb, 0xff, 0xfe, 'A', 'l'}
+	const raw = `-__-QWw` // needs one padding character
+
+	dec := byteslice.NewHeuristicDecoder().LenientPadding(true)
+	testcases := []struct {
+		Name     string
+		Source   string
+		Expected []byte
+	}{
+		{Name: "no padding needed, 0 stray", Source: header, Expected: []byte(`{"alg":"HS256"}`)},
+		{Name: "no padding needed, 1 stray", Source: header + `=`, Expected: []byte(`{"alg":"HS256"}`)},
+		{Name: "no padding needed, 2 stray", Source: header + `==`, Expected: []byte(`{"alg":"HS256"}`)},
+		{Name: "padding needed, 0 stray", Source: raw, Expected: payload},
+		{Name: "padding needed, 1 stray", Source: raw + `=`, Expected: payload},
+		{Name: "padding needed, 2 stray", Source: raw + `==`, Expected: payload},
+		{Name: "two padding needed, 1 stray", Source: `QQ=`, Expected: []byte(`A`)},
+		{Name: "two padding needed, 3 stray", Source: `QQ===`, Expected: []byte(`A`)},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			buf, err := dec.DecodeString(tc.Source)
+			require.NoError(t, err, `DecodeString should succeed`)
+			require.Equal(t, tc.Expected, buf)
+		})
+	}
+	t.Run("default rejects stray padding", func(t *testing.T) {
+		_, err := byteslice.NewHeuristicDecoder().DecodeString(header + `=`)
+		require.Error(t, err, `DecodeString should fail`)
+	})
+	t.Run("mutually exclusive with StrictPadding", func(t *testing.T) {
+		d := byteslice.NewHeuristicDecoder().LenientPadding(true).StrictPadding(true)
+		_, err := d.DecodeString(`QQ===`)
+		require.Error(t, err, `StrictPadding should take over`)
+
+		d.LenientPadding(true)
+		_, err = d.DecodeString(`QQ===`)
+		require.NoError(t, err, `LenientPadding should take over`)
+	})
+}
+
+func TestIsText(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Payload  []byte
+		Expected bool
+	}{
+		{Name: "ASCII text", Payload: []byte("Alice and Bob\n\tsaid \"hi\"\r\n"), Expected: true},
+		{Name: "UTF-8 text", Payload: []byte(`こんにちは, Алиса 👋`), Expected: true},
+		{Name: "empty", Payload: []byte{}, Expected: true},
+		{Name: "invalid UTF-8", Payload: []byte{'A', 0xff, 0xfe}},
+		{Name: "NUL byte", Payload: []byte("Alice\x00")},
+		{Name: "escape sequence", Payload: []byte("\x1b[31mAlice")},
+		{Name: "DEL", Payload: []byte("Alice\x7f")},
+		{Name: "binary", Payload: []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a}},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expected, byteslice.New(tc.Payload).IsText())
+		})
+	}
+}
+
+func TestSuggestEncoding(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Payload  []byte
+		Expected string
+	}{
+		{Name: "ASCII text", Payload: []byte("Alice and Bob\n\tsaid \"hi\"\r\n"), Expected: "ascii"},
+		{Name: "empty", Payload: []byte{}, Expected: "ascii"},
+		{Name: "URL-safe binary", Payload: []byte{0x00, 0x10, 0x83, 0x10, 0x51, 0x87}, Expected: "base64url"},
+		{Name: "URL-safe binary, partial group", Payload: []byte{0x00, 0x80, 0x01, 0xf0}, Expected: "base64url"},
+		{Name: "UTF-8 text", Payload: []byte(`Алиса`), Expected: "base64url"},
+		{Name: "binary", Payload: []byte{0xfb, 0xff, 0xfe}, Expected: "base64"},
+		{Name: "binary, partial group", Payload: []byte{0x00, 0x00, 0x00, 0xfc}, Expected: "base64"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			if tc.Expected == "base64url" {
+				require.NotContains(t, base64.StdEncoding.EncodeToString(tc.Payload), "+", `test payload should be URL-safe`)
+				require.NotContains(t, base64.StdEncoding.EncodeToString(tc.Payload), "/", `test payload should be URL-safe`)
+			}
+			require.Equal(t, tc.Expected, byteslice.New(tc.Payload).SuggestEncoding())
+		})
+	}
+}
+
+func TestHeuristicDecoderStrictPadding(t *testing.T) {
+	testcases := []struct {
+		Name   string
+		Source string
+		Error  bool
+	}{
+		{Name: "no padding", Source: `QWxpY2U`},
+		{Name: "one padding", Source: `QWxpY2U=`},
+		{Name: "two padding", Source: `QWxpYw==`},
+		{Name: "three padding", Source: `QWxpYw===`, Error: true},
+		{Name: "mid-string padding", Source: `QQ==QWxpY2U=`, Error: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := byteslice.NewHeuristicDecoder().StrictPadding(true).DecodeString(tc.Source)
+			if tc.Error {
+				require.Error(t, err, `DecodeString should fail`)
+				require.Contains(t, err.Error(), `padding`)
+				return
+			}
+			require.NoError(t, err, `DecodeString should succeed`)
+		})
+	}
+}
+
+func TestHeuristicDecoderSetPadding(t *testing.T) {
+	payload := []byte{0xfb, 0xff, 'A', 'l'}
+	dec := byteslice.NewHeuristicDecoder().SetPadding('.')
+
+	testcases := []*base64.Encoding{
+		base64.StdEncoding.WithPadding('.'),
+		base64.URLEncoding.WithPadding('.'),
+		base64.RawStdEncoding,
+		base64.RawURLEncoding,
+	}
+	for _, enc := range testcases {
+		enc := enc
+		encoded := enc.EncodeToString(payload)
+		t.Run(encoded, func(t *testing.T) {
+			var v byteslice.Buffer
+			v.SetB64Decoder(dec)
+			v.SetRoundTripEncoding(true)
+			require.NoError(t, v.DecodeString(encoded), `DecodeString should succeed`)
+			require.Equal(t, payload, v.Bytes())
+			require.Equal(t, encoded, v.EncodeToString(), `should round-trip using the same padding`)
+		})
+	}
+	t.Run("standard padding is rejected", func(t *testing.T) {
+		_, err := dec.DecodeString(`+/9BbA==`)
+		require.Error(t, err, `DecodeString should fail`)
+	})
+	t.Run("strict padding", func(t *testing.T) {
+		strict := byteslice.NewHeuristicDecoder().SetPadding('.').StrictPadding(true)
+		_, err := strict.DecodeString(`QWxpYw...`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidPadding), `error should match ErrInvalidPadding`)
+		_, err = strict.DecodeString(`....`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidPadding), `error should match ErrInvalidPadding`)
+	})
+	t.Run("max decode length", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Decoder(dec)
+		v.SetMaxDecodeLen(4)
+		require.NoError(t, v.DecodeString(`+/9BbA..`), `DecodeString should succeed`)
+	})
+	t.Run("reset to default", func(t *testing.T) {
+		buf, err := byteslice.NewHeuristicDecoder().SetPadding('.').SetPadding('=').DecodeString(`QWxpY2U=`)
+		require.NoError(t, err, `DecodeString should succeed`)
+		require.Equal(t, []byte(`Alice`), buf)
+	})
+	t.Run("invalid padding character", func(t *testing.T) {
+		require.Panics(t, func() { byteslice.NewHeuristicDecoder().SetPadding('-') })
+		require.Panics(t, func() { byteslice.NewHeuristicDecoder().SetPadding('é') })
+	})
+}
+
+func TestEncodeToString(t *testing.T) {
+	for _, enc := range []byteslice.B64Encoder{base64.StdEncoding, base64.RawURLEncoding, byteslice.HexEncoder} {
+		v := byteslice.New([]byte{0xfb, 0xff, 0xfe}, byteslice.WithB64Encoder(enc))
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		unquoted, err := strconv.Unquote(string(buf))
+		require.NoError(t, err, `strconv.Unquote should succeed`)
+		require.Equal(t, unquoted, v.EncodeToString())
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.DecodeString(`QWxpY2U`), `DecodeString should succeed`)
+		require.Equal(t, []byte(`Alice`), v.Bytes())
+
+		v.SetB64Decoder(byteslice.HexDecoder)
+		require.NoError(t, v.DecodeString(`426f62`), `DecodeString should succeed`)
+		require.Equal(t, []byte(`Bob`), v.Bytes())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.Error(t, v.DecodeString(`!!!`), `DecodeString should fail`)
+		require.Equal(t, []byte(`Alice`), v.Bytes(), `contents should be left untouched`)
+	})
+}
+
+func TestDecodeStringStrict(t *testing.T) {
+	testcases := []struct {
+		Name     string
+		Source   string
+		Decoder  byteslice.B64Decoder
+		Position string
+	}{
+		{Name: "valid", Source: `QWxpY2U=`},
+		{Name: "valid raw URL", Source: `-__-QWw`},
+		{Name: "valid with explicit decoder", Source: `QWxpY2U`, Decoder: base64.RawStdEncoding},
+		{Name: "trailing space", Source: `QWxpY2U= `, Position: `position 8`},
+		{Name: "trailing newline", Source: "QWxpY2U=\n", Position: `position 8`},
+		{Name: "trailing non-alphabet character", Source: `QWxpY2U=!`, Position: `position 8`},
+		{Name: "trailing data after padding", Source: `QQ==QQ==`, Position: `position 4`},
+		{Name: "mixed alphabets", Source: `+_8`, Position: `position 1`},
+		{Name: "invalid length", Source: `QWxpY`, Position: `position 4`},
+		{Name: "non-zero padding bits", Source: `QWxpY2V=`, Position: `position 7`},
+		{Name: "wrong alphabet for explicit decoder", Source: `-__-`, Decoder: base64.StdEncoding, Position: `position 0`},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New([]byte(`stale`))
+			v.SetB64Decoder(tc.Decoder)
+			err := v.DecodeStringStrict(tc.Source)
+			if tc.Position != "" {
+				require.Error(t, err, `DecodeStringStrict should fail`)
+				require.Contains(t, err.Error(), tc.Position)
+				require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+				require.Equal(t, []byte(`stale`), v.Bytes(), `contents should be left untouched`)
+				return
+			}
+			require.NoError(t, err, `DecodeStringStrict should succeed`)
+
+			expected, _ := byteslice.HeuristicB64Decoder.DecodeString(tc.Source)
+			require.Equal(t, expected, v.Bytes())
+		})
+	}
+	t.Run("heuristic decoder accepts trailing space", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.NoError(t, v.DecodeString(`QWxpY2U= `), `DecodeString should succeed`)
+	})
+	t.Run("global decoder", func(t *testing.T) {
+		defer byteslice.SetGlobalB64Decoder(byteslice.HeuristicB64Decoder)
+		byteslice.SetGlobalB64Decoder(base64.StdEncoding)
+
+		var v byteslice.Buffer
+		err := v.DecodeStringStrict(`-__-`)
+		require.Error(t, err, `DecodeStringStrict should use the global decoder`)
+		require.Contains(t, err.Error(), `position 0`)
+		require.NoError(t, v.DecodeStringStrict(`+//+`), `DecodeStringStrict should succeed`)
+		require.Equal(t, base64.StdEncoding, v.LastDecodeEncoding())
+	})
+}
+
+func TestXML(t *testing.T) {
+	type foo struct {
+		XMLName xml.Name         `xml:"foo"`
+		Bar     byteslice.Buffer `xml:"bar"`
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		var src foo
+		src.Bar.SetBytes([]byte(`Alice`))
+
+		buf, err := xml.Marshal(src)
+		require.NoError(t, err, `xml.Marshal should succeed`)
+		require.Equal(t, `<foo><bar>QWxpY2U=</bar></foo>`, string(buf))
+
+		var dst foo
+		require.NoError(t, xml.Unmarshal(buf, &dst), `xml.Unmarshal should succeed`)
+		require.Equal(t, []byte(`Alice`), dst.Bar.Bytes())
+	})
+	t.Run("empty element", func(t *testing.T) {
+		var dst foo
+		dst.Bar.SetBytes([]byte(`stale`))
+		require.NoError(t, xml.Unmarshal([]byte(`<foo><bar></bar></foo>`), &dst), `xml.Unmarshal should succeed`)
+		require.Equal(t, 0, dst.Bar.Len())
+	})
+}
+
+func TestBytesCopy(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+	data := v.BytesCopy()
+	require.Equal(t, v.Bytes(), data)
+
+	data[0] = 'a'
+	require.Equal(t, []byte(`Alice`), v.Bytes(), `modifying the copy should not affect the buffer`)
+
+	v.SetBytes([]byte(`Bob`))
+	require.Equal(t, []byte(`alice`), data, `modifying the buffer should not affect the copy`)
+
+	var nilbuf *byteslice.Buffer
+	require.Nil(t, nilbuf.BytesCopy())
+}
+
+func TestBytesOrNil(t *testing.T) {
+	t.Run("New(nil)", func(t *testing.T) {
+		v := byteslice.New(nil)
+		require.Nil(t, v.Bytes())
+		require.Nil(t, v.BytesOrNil())
+	})
+	t.Run("New([]byte{})", func(t *testing.T) {
+		v := byteslice.New([]byte{})
+		require.NotNil(t, v.Bytes())
+		require.Len(t, v.Bytes(), 0)
+		require.Nil(t, v.BytesOrNil())
+	})
+	t.Run("SetBytes([]byte{})", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetBytes([]byte{})
+		require.NotNil(t, v.Bytes())
+		require.Len(t, v.Bytes(), 0)
+		require.Nil(t, v.BytesOrNil())
+	})
+	t.Run("non-empty", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.Equal(t, []byte(`Alice`), v.BytesOrNil())
+	})
+	t.Run("nil receiver", func(t *testing.T) {
+		var v *byteslice.Buffer
+		require.Nil(t, v.BytesOrNil())
+	})
+}
+
+func TestSetBytesNoCopy(t *testing.T) {
+	src := []byte(`Alice`)
+
+	var v byteslice.Buffer
+	v.SetBytesNoCopy(src)
+	require.Equal(t, []byte(`Alice`), v.Bytes())
+
+	src[0] = 'a'
+	require.Equal(t, []byte(`alice`), v.Bytes(), `changes to the adopted slice should be visible`)
+
+	v.SetBytesNoCopy(nil)
+	require.True(t, v.IsNil(), `SetBytesNoCopy(nil) should leave the buffer nil`)
+}
+
+func TestSwap(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+
+	src := []byte(`Bob`)
+	old := v.Swap(src)
+	require.Equal(t, []byte(`Alice`), old, `Swap should return the previous contents`)
+	require.Equal(t, []byte(`Bob`), v.Bytes())
+
+	src[0] = 'b'
+	require.Equal(t, []byte(`Bob`), v.Bytes(), `modifying the source should not affect the buffer`)
+
+	old[0] = 'a'
+	require.Equal(t, []byte(`Bob`), v.Bytes(), `modifying the returned slice should not affect the buffer`)
+
+	old = v.Swap(nil)
+	require.Equal(t, []byte(`Bob`), old)
+	require.True(t, v.IsNil(), `Swap(nil) should leave the buffer nil`)
+}
+
+func TestCopyTo(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+	testcases := []struct {
+		Name     string
+		Size     int
+		Expected []byte
+	}{
+		{Name: "shorter", Size: 3, Expected: []byte(`Ali`)},
+		{Name: "equal", Size: 5, Expected: []byte(`Alice`)},
+		{Name: "longer", Size: 8, Expected: []byte{'A', 'l', 'i', 'c', 'e', 0, 0, 0}},
+		{Name: "empty", Size: 0, Expected: []byte{}},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			dst := make([]byte, tc.Size)
+			n := v.CopyTo(dst)
+			require.Equal(t, tc.Size, len(dst))
+			require.Equal(t, tc.Expected, dst)
+			if tc.Size < v.Len() {
+				require.Equal(t, tc.Size, n)
+			} else {
+				require.Equal(t, v.Len(), n)
+			}
+		})
+	}
+	t.Run("nil receiver", func(t *testing.T) {
+		var nilbuf *byteslice.Buffer
+		require.Equal(t, 0, nilbuf.CopyTo(make([]byte, 4)))
+	})
+}
+
+func TestWriteString(t *testing.T) {
+	var v byteslice.Buffer
+	for _, chunk := range []string{`Alice`, ` and `, `Bob`} {
+		n, err := v.WriteString(chunk)
+		require.NoError(t, err, `WriteString should succeed`)
+		require.Equal(t, len(chunk), n)
+	}
+	require.Equal(t, []byte(`Alice and Bob`), v.Bytes())
+}
+
+// writeBytes converts `s` and writes it to `w`. Calling Write through an
+// interface from a function that is not inlined keeps the compiler from
+// proving that the converted []byte does not escape, as is the case for
+// most callers of io.Writer, so the conversion allocates.
+//
+//go:noinline
+func writeBytes(w io.Writer, s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func BenchmarkWriteString(b *testing.B) {
+	s := strings.Repeat(`x`, 64)
+	b.Run("WriteString", func(b *testing.B) {
+		var v byteslice.Buffer
+		v.Grow(64)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			v.Truncate(0)
+			_, _ = v.WriteString(s)
+		}
+	})
+	b.Run("Write([]byte(s))", func(b *testing.B) {
+		var v byteslice.Buffer
+		v.Grow(64)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			v.Truncate(0)
+			_, _ = writeBytes(&v, s)
+		}
+	})
+}
+
+func TestMarshalJSONReceiver(t *testing.T) {
+	type byValue struct {
+		Bar byteslice.Buffer `json:"bar"`
+	}
+	type byPointer struct {
+		Bar *byteslice.Buffer `json:"bar"`
+	}
+
+	v := byteslice.New([]byte(`Alice`))
+	for _, src := range []interface{}{byValue{Bar: *v}, &byValue{Bar: *v}, byPointer{Bar: v}, &byPointer{Bar: v}} {
+		buf, err := json.Marshal(src)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `{"bar":"QWxpY2U="}`, string(buf), `%T should be marshaled using MarshalJSON`, src)
+	}
+}
+
+func TestMarshalJSONNilPointer(t *testing.T) {
+	type byPointer struct {
+		Bar *byteslice.Buffer `json:"bar"`
+		Baz *byteslice.Buffer `json:"baz,omitempty"`
+	}
+
+	buf, err := json.Marshal(byPointer{})
+	require.NoError(t, err, `json.Marshal should succeed`)
+	require.Equal(t, `{"bar":null}`, string(buf))
+
+	var nilbuf *byteslice.Buffer
+	buf, err = json.Marshal(nilbuf)
+	require.NoError(t, err, `json.Marshal should succeed`)
+	require.Equal(t, `null`, string(buf))
+
+	require.NotPanics(t, func() { _ = nilbuf.EncodeToString() }, `EncodeToString should be nil-safe`)
+
+	var dst byPointer
+	require.NoError(t, json.Unmarshal([]byte(`{"bar":null}`), &dst), `json.Unmarshal should succeed`)
+	require.Nil(t, dst.Bar)
+}
+
+func TestMaxDecodeLen(t *testing.T) {
+	payload := []byte(`Alice`)
+	testcases := []struct {
+		Name    string
+		Decoder byteslice.B64Decoder
+		Encoded string
+	}{
+		{Name: "heuristic", Decoder: byteslice.HeuristicB64Decoder, Encoded: base64.StdEncoding.EncodeToString(payload)},
+		{Name: "base64.Encoding", Decoder: base64.RawURLEncoding, Encoded: base64.RawURLEncoding.EncodeToString(payload)},
+		{Name: "hex", Decoder: byteslice.HexDecoder, Encoded: byteslice.HexEncoder.EncodeToString(payload)},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(nil, byteslice.WithB64Decoder(tc.Decoder))
+
+			v.SetMaxDecodeLen(len(payload))
+			require.NoError(t, v.DecodeString(tc.Encoded), `DecodeString should succeed at exactly the limit`)
+			require.Equal(t, payload, v.Bytes())
+			require.NoError(t, json.Unmarshal([]byte(strconv.Quote(tc.Encoded)), v), `json.Unmarshal should succeed at exactly the limit`)
+			require.Equal(t, payload, v.Bytes())
+
+			v.SetMaxDecodeLen(len(payload) - 1)
+			require.Error(t, v.DecodeString(tc.Encoded), `DecodeString should fail when exceeding the limit`)
+			err := json.Unmarshal([]byte(strconv.Quote(tc.Encoded)), v)
+			require.Error(t, err, `json.Unmarshal should fail when exceeding the limit`)
+			require.True(t, errors.Is(err, byteslice.ErrMaxLenExceeded), `error should match ErrMaxLenExceeded`)
+			_, err = v.DecodeFrom(strings.NewReader(tc.Encoded))
+			require.Error(t, err, `DecodeFrom should fail when exceeding the limit`)
+
+			v.SetMaxDecodeLen(0)
+			require.NoError(t, v.DecodeString(tc.Encoded), `DecodeString should succeed without a limit`)
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	t.Run("Slice", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice and Bob`), byteslice.WithB64Encoder(byteslice.HexEncoder))
+		s := v.Slice(6, 9)
+		require.Equal(t, []byte(`and`), s.Bytes())
+		require.Equal(t, byteslice.HexEncoder, s.B64Encoder(), `encoder should be carried over`)
+
+		s.Bytes()[0] = 'A'
+		require.Equal(t, []byte(`Alice and Bob`), v.Bytes(), `Slice should not share storage`)
+	})
+	t.Run("SliceView", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice and Bob`))
+		s := v.SliceView(6, 9)
+		require.Equal(t, []byte(`and`), s.Bytes())
+
+		s.Bytes()[0] = 'A'
+		require.Equal(t, []byte(`Alice And Bob`), v.Bytes(), `SliceView should share storage`)
+
+		s.Append('!')
+		require.Equal(t, []byte(`Alice And Bob`), v.Bytes(), `appending to the view should not overwrite the original`)
+	})
+	t.Run("bounds", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		require.Equal(t, 0, v.Slice(5, 5).Len())
+		require.Panics(t, func() { v.Slice(0, 6) }, `Slice should panic`)
+		require.Panics(t, func() { v.SliceView(3, 2) }, `SliceView should panic`)
+	})
+}
+
+func TestAscii85(t *testing.T) {
+	testcases := []struct {
+		Name    string
+		Payload []byte
+		Encoded string
+	}{
+		{Name: "text", Payload: []byte(`Alice`), Encoded: `6#:7FAH`},
+		{Name: "zero group", Payload: []byte{0, 0, 0, 0, 'A'}, Encoded: `z5l`},
+		{Name: "empty", Payload: []byte{}, Encoded: ``},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(tc.Payload, byteslice.WithB64Encoder(byteslice.Ascii85Encoder), byteslice.WithB64Decoder(byteslice.Ascii85Decoder))
+			require.Equal(t, tc.Encoded, v.EncodeToString())
+
+			var rt byteslice.Buffer
+			rt.SetB64Decoder(byteslice.Ascii85Decoder)
+			require.NoError(t, rt.DecodeString(tc.Encoded), `DecodeString should succeed`)
+			require.Equal(t, tc.Payload, rt.Bytes())
+		})
+	}
+	t.Run("invalid", func(t *testing.T) {
+		_, err := byteslice.Ascii85Decoder.DecodeString(`~~~`)
+		require.Error(t, err, `DecodeString should fail`)
+	})
+}
+
+func TestStripWhitespace(t *testing.T) {
+	payload := make([]byte, 200)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+
+	// Wrap at 76 columns, as MIME does, with some extra indentation
+	encoded := base64.StdEncoding.EncodeToString(payload)
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString("\t" + encoded[:76] + " \r\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString("\t" + encoded + " \r\n")
+
+	for _, dec := range []byteslice.B64Decoder{byteslice.HeuristicB64Decoder, base64.StdEncoding} {
+		_, err := dec.DecodeString(wrapped.String())
+		require.Error(t, err, `%T should fail without stripping whitespace`, dec)
+
+		buf, err := byteslice.StripWhitespace(dec).DecodeString(wrapped.String())
+		require.NoError(t, err, `%T should succeed after stripping whitespace`, dec)
+		require.Equal(t, payload, buf)
+	}
+}
+
+func TestGob(t *testing.T) {
+	type foo struct {
+		Key   byteslice.Buffer
+		Nonce byteslice.Buffer
+	}
+
+	var src foo
+	src.Key.SetBytes(bytes.Repeat([]byte{0xff}, 3072))
+	src.Nonce.SetBytes([]byte{0x00, 0x01, 0x02})
+
+	data, err := src.Key.GobEncode()
+	require.NoError(t, err, `GobEncode should succeed`)
+	require.Equal(t, src.Key.Bytes(), data, `gob form should be the raw bytes`)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(src), `gob.Encode should succeed`)
+	require.Less(t, buf.Len(), base64.StdEncoding.EncodedLen(src.Key.Len()), `gob output should not be base64 expanded`)
+
+	var dst foo
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&dst), `gob.Decode should succeed`)
+	require.Equal(t, src.Key.Bytes(), dst.Key.Bytes())
+	require.Equal(t, src.Nonce.Bytes(), dst.Nonce.Bytes())
+}
+
+func TestFormat(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+	testcases := []struct {
+		Format   string
+		Expected string
+	}{
+		{Format: `%s`, Expected: `QWxpY2U=`},
+		{Format: `%v`, Expected: `QWxpY2U=`},
+		{Format: `%q`, Expected: `"QWxpY2U="`},
+		{Format: `%x`, Expected: `416c696365`},
+		{Format: `%X`, Expected: `416C696365`},
+		{Format: `% x`, Expected: `41 6c 69 63 65`},
+		{Format: `%12s`, Expected: `    QWxpY2U=`},
+		{Format: `%#v`, Expected: `byteslice.Buffer{[]byte{0x41, 0x6c, 0x69, 0x63, 0x65}}`},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Format, func(t *testing.T) {
+			require.Equal(t, tc.Expected, fmt.Sprintf(tc.Format, v))
+		})
+	}
+	t.Run("struct field", func(t *testing.T) {
+		type Foo struct {
+			Bar byteslice.Buffer
+			Baz *byteslice.Buffer
+		}
+		foo := Foo{Bar: *v}
+		require.Equal(t, `{QWxpY2U= <nil>}`, fmt.Sprintf(`%v`, foo))
+		require.Equal(t, `{416c696365 <nil>}`, fmt.Sprintf(`%x`, Foo{Bar: *v}))
+		require.Equal(t, `416c696365`, fmt.Sprintf(`%x`, *v))
+	})
+	t.Run("unsupported verb", func(t *testing.T) {
+		require.Equal(t, `%!d(byteslice.Buffer=QWxpY2U=)`, fmt.Sprintf(`%d`, v))
+	})
+}
+
+func TestSum(t *testing.T) {
+	v := byteslice.New([]byte(`Alice`))
+
+	expected := sha256.Sum256(v.Bytes())
+	require.Equal(t, expected, v.SHA256())
+	require.Equal(t, expected[:], v.Sum(sha256.New()))
+
+	h := md5.New()
+	h.Write(v.Bytes())
+	require.Equal(t, h.Sum(nil), v.Sum(md5.New()))
+}
+
+func TestSplit(t *testing.T) {
+	testcases := []struct {
+		Name string
+		Data []byte
+		Sep  []byte
+	}{
+		{Name: "simple", Data: []byte(`a,b,c`), Sep: []byte(`,`)},
+		{Name: "multi-byte separator", Data: []byte{1, 0, 0, 2, 0, 0, 3}, Sep: []byte{0, 0}},
+		{Name: "leading and trailing separator", Data: []byte(`,a,,b,`), Sep: []byte(`,`)},
+		{Name: "separator not found", Data: []byte(`Alice`), Sep: []byte(`,`)},
+		{Name: "empty separator", Data: []byte(`Alé`), Sep: []byte{}},
+		{Name: "empty data", Data: []byte{}, Sep: []byte(`,`)},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(tc.Data, byteslice.WithB64Encoder(byteslice.HexEncoder))
+			parts := v.Split(tc.Sep)
+
+			expected := bytes.Split(tc.Data, tc.Sep)
+			require.Len(t, parts, len(expected))
+			for i, part := range parts {
+				require.Equal(t, expected[i], part.Bytes(), `part %d should match bytes.Split`, i)
+				require.Equal(t, byteslice.HexEncoder, part.B64Encoder(), `configuration should be carried over`)
+			}
+		})
+	}
+	t.Run("copies", func(t *testing.T) {
+		v := byteslice.New([]byte(`a,b`))
+		parts := v.Split([]byte(`,`))
+		parts[0].Append('x')
+		parts[1].Bytes()[0] = 'B'
+		require.Equal(t, []byte(`a,b`), v.Bytes(), `parts should not share storage with the buffer`)
+	})
+}
+
+func TestXorInPlace(t *testing.T) {
+	original := []byte(`Alice`)
+	testcases := []struct {
+		Name     string
+		Key      []byte
+		Expected []byte
+	}{
+		{Name: "shorter key", Key: []byte{0x01, 0x02}, Expected: []byte{'A' ^ 1, 'l' ^ 2, 'i' ^ 1, 'c' ^ 2, 'e' ^ 1}},
+		{Name: "equal length key", Key: []byte{1, 2, 3, 4, 5}, Expected: []byte{'A' ^ 1, 'l' ^ 2, 'i' ^ 3, 'c' ^ 4, 'e' ^ 5}},
+		{Name: "longer key", Key: []byte{1, 2, 3, 4, 5, 6, 7}, Expected: []byte{'A' ^ 1, 'l' ^ 2, 'i' ^ 3, 'c' ^ 4, 'e' ^ 5}},
+		{Name: "empty key", Key: []byte{}, Expected: original},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(original)
+			v.XorInPlace(tc.Key)
+			require.Equal(t, tc.Expected, v.Bytes())
+
+			v.XorInPlace(tc.Key)
+			require.Equal(t, original, v.Bytes(), `XOR twice should restore the original`)
+		})
+	}
+	t.Run("nil receiver", func(t *testing.T) {
+		var nilbuf *byteslice.Buffer
+		require.NotPanics(t, func() { nilbuf.XorInPlace([]byte{1}) })
+	})
+}
+
+func TestHexDump(t *testing.T) {
+	v := byteslice.New([]byte("Alice and Bob\x00\x01\xff"))
+	require.Equal(t, hex.Dump(v.Bytes()), v.HexDump())
+	require.Equal(t, "00000000  41 6c 69 63 65 20 61 6e  64 20 42 6f 62 00 01 ff  |Alice and Bob...|\n", v.HexDump())
+
+	var nilbuf *byteslice.Buffer
+	require.Equal(t, ``, nilbuf.HexDump())
+}
+
+func TestDecodeValidator(t *testing.T) {
+	valid := base64.StdEncoding.EncodeToString([]byte(`こんにちは, Alice`))
+	invalid := base64.StdEncoding.EncodeToString([]byte{'A', 0xff, 0xfe})
+
+	t.Run("valid", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetDecodeValidator(byteslice.ValidateUTF8)
+		require.NoError(t, v.DecodeString(valid), `DecodeString should succeed`)
+		require.Equal(t, `こんにちは, Alice`, string(v.Bytes()))
+	})
+	t.Run("invalid", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		v.SetDecodeValidator(byteslice.ValidateUTF8)
+		require.Error(t, v.DecodeString(invalid), `DecodeString should fail`)
+		require.Error(t, json.Unmarshal([]byte(strconv.Quote(invalid)), v), `json.Unmarshal should fail`)
+		require.Error(t, v.AcceptValue(invalid), `AcceptValue should fail`)
+		require.Equal(t, []byte(`Alice`), v.Bytes(), `contents should be left untouched`)
+	})
+	t.Run("removed", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetDecodeValidator(byteslice.ValidateUTF8)
+		v.SetDecodeValidator(nil)
+		require.NoError(t, v.DecodeString(invalid), `DecodeString should succeed`)
+	})
+}
+
+func TestQuotedPrintable(t *testing.T) {
+	testcases := []struct {
+		Name    string
+		Payload []byte
+		Encoded string
+	}{
+		{Name: "equals sign", Payload: []byte(`a=b`), Encoded: `a=3Db`},
+		{Name: "high bytes", Payload: []byte{'A', 0xff, 0x80}, Encoded: `A=FF=80`},
+		{Name: "trailing spaces", Payload: []byte(`trailing  `), Encoded: `trailing =20`},
+		{Name: "line break", Payload: []byte("line\r\nbreak"), Encoded: `line=0D=0Abreak`},
+		{Name: "empty", Payload: []byte{}, Encoded: ``},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(tc.Payload, byteslice.WithB64Encoder(byteslice.QuotedPrintableEncoder))
+			require.Equal(t, tc.Encoded, v.EncodeToString())
+
+			var rt byteslice.Buffer
+			rt.SetB64Decoder(byteslice.QuotedPrintableDecoder)
+			require.NoError(t, rt.DecodeString(tc.Encoded), `DecodeString should succeed`)
+			require.Equal(t, tc.Payload, rt.Bytes())
+		})
+	}
+	t.Run("long line", func(t *testing.T) {
+		payload := bytes.Repeat([]byte{'x', '='}, 100)
+		v := byteslice.New(payload, byteslice.WithB64Encoder(byteslice.QuotedPrintableEncoder))
+
+		var rt byteslice.Buffer
+		rt.SetB64Decoder(byteslice.QuotedPrintableDecoder)
+		require.NoError(t, rt.DecodeString(v.EncodeToString()), `DecodeString should succeed`)
+		require.Equal(t, payload, rt.Bytes())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := byteslice.QuotedPrintableDecoder.DecodeString("a\x01b")
+		require.Error(t, err, `DecodeString should fail`)
+	})
+}
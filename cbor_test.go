@@ -0,0 +1,27 @@
+package byteslice_test
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBOR(t *testing.T) {
+	type foo struct {
+		Bar byteslice.Buffer `cbor:"bar"`
+	}
+
+	var src foo
+	src.Bar.SetBytes([]byte{0x00, 0xde, 0xad, 0xbe, 0xef})
+
+	buf, err := cbor.Marshal(src)
+	require.NoError(t, err, `cbor.Marshal should succeed`)
+	// map(1) { text(3) "bar": bytes(5) h'00deadbeef' }
+	require.Equal(t, []byte{0xa1, 0x63, 'b', 'a', 'r', 0x45, 0x00, 0xde, 0xad, 0xbe, 0xef}, buf)
+
+	var dst foo
+	require.NoError(t, cbor.Unmarshal(buf, &dst), `cbor.Unmarshal should succeed`)
+	require.Equal(t, src.Bar.Bytes(), dst.Bar.Bytes())
+}
@@ -0,0 +1,73 @@
+package byteslice
+
+import (
+	"fmt"
+	"io"
+)
+
+// Cursor is a stateful view of a `Buffer` object that implements
+// `io.Reader`, `io.Writer`, and `io.Seeker`, much like a file does.
+// The contents are stored in the `Buffer` object, while the Cursor
+// maintains its own offset. Multiple Cursors on the same `Buffer`
+// object each have independent offsets.
+//
+// Like `Buffer`, Cursor is not synchronized.
+type Cursor struct {
+	buf *Buffer
+	off int64
+}
+
+// Cursor creates a new `Cursor` object positioned at the beginning
+// of the buffer.
+func (b *Buffer) Cursor() *Cursor {
+	return &Cursor{buf: b}
+}
+
+// Read implements `io.Reader`. It reads from the current offset, and
+// returns `io.EOF` once the offset reaches the end of the buffer.
+func (c *Cursor) Read(p []byte) (int, error) {
+	if c.off >= int64(len(c.buf.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, c.buf.data[c.off:])
+	c.off += int64(n)
+	return n, nil
+}
+
+// Write implements `io.Writer`. It overwrites the contents of the buffer
+// starting at the current offset, extending the buffer as necessary.
+// If the offset is past the end of the buffer, the gap is filled with
+// zeros.
+func (c *Cursor) Write(p []byte) (int, error) {
+	end := c.off + int64(len(p))
+	if l := int64(len(c.buf.data)); end > l {
+		// the compiler recognizes this idiom, and does not allocate
+		// the zero-filled slice separately
+		c.buf.data = append(c.buf.data, make([]byte, end-l)...)
+	}
+	n := copy(c.buf.data[c.off:], p)
+	c.off += int64(n)
+	return n, nil
+}
+
+// Seek implements `io.Seeker`. Seeking past the end of the buffer is
+// allowed; subsequent reads return `io.EOF`, and subsequent writes
+// extend the buffer. Seeking to a negative offset is an error.
+func (c *Cursor) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = c.off + offset
+	case io.SeekEnd:
+		abs = int64(len(c.buf.data)) + offset
+	default:
+		return 0, fmt.Errorf(`failed to seek byteslice.Cursor: invalid whence %d`, whence)
+	}
+	if abs < 0 {
+		return 0, fmt.Errorf(`failed to seek byteslice.Cursor: negative position %d`, abs)
+	}
+	c.off = abs
+	return abs, nil
+}
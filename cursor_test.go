@@ -0,0 +1,85 @@
+package byteslice_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("seek and read", func(t *testing.T) {
+		v := byteslice.New([]byte(`0123456789`))
+		c := v.Cursor()
+
+		buf := make([]byte, 3)
+		n, err := c.Read(buf)
+		require.NoError(t, err, `Read should succeed`)
+		require.Equal(t, `012`, string(buf[:n]))
+
+		pos, err := c.Seek(2, io.SeekCurrent)
+		require.NoError(t, err, `Seek should succeed`)
+		require.Equal(t, int64(5), pos)
+		n, err = c.Read(buf)
+		require.NoError(t, err, `Read should succeed`)
+		require.Equal(t, `567`, string(buf[:n]))
+
+		pos, err = c.Seek(1, io.SeekStart)
+		require.NoError(t, err, `Seek should succeed`)
+		require.Equal(t, int64(1), pos)
+		n, err = c.Read(buf)
+		require.NoError(t, err, `Read should succeed`)
+		require.Equal(t, `123`, string(buf[:n]))
+
+		pos, err = c.Seek(-2, io.SeekEnd)
+		require.NoError(t, err, `Seek should succeed`)
+		require.Equal(t, int64(8), pos)
+		rest, err := io.ReadAll(c)
+		require.NoError(t, err, `io.ReadAll should succeed`)
+		require.Equal(t, `89`, string(rest))
+
+		_, err = c.Read(buf)
+		require.Equal(t, io.EOF, err, `Read at the end should return io.EOF`)
+	})
+	t.Run("invalid seek", func(t *testing.T) {
+		c := byteslice.New([]byte(`Alice`)).Cursor()
+		_, err := c.Seek(-1, io.SeekStart)
+		require.Error(t, err, `Seek to a negative position should fail`)
+		_, err = c.Seek(0, 42)
+		require.Error(t, err, `Seek with an invalid whence should fail`)
+	})
+	t.Run("write", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		c := v.Cursor()
+
+		_, err := c.Seek(1, io.SeekStart)
+		require.NoError(t, err, `Seek should succeed`)
+		n, err := c.Write([]byte(`LICE and Bob`))
+		require.NoError(t, err, `Write should succeed`)
+		require.Equal(t, 12, n)
+		require.Equal(t, `ALICE and Bob`, string(v.Bytes()))
+
+		_, err = c.Seek(2, io.SeekEnd)
+		require.NoError(t, err, `Seek should succeed`)
+		_, err = c.Write([]byte(`!`))
+		require.NoError(t, err, `Write should succeed`)
+		require.Equal(t, "ALICE and Bob\x00\x00!", string(v.Bytes()), `gap should be zero-filled`)
+	})
+	t.Run("independent offsets", func(t *testing.T) {
+		v := byteslice.New([]byte(`Alice`))
+		c1 := v.Cursor()
+		c2 := v.Cursor()
+
+		_, err := c1.Seek(3, io.SeekStart)
+		require.NoError(t, err, `Seek should succeed`)
+
+		buf := make([]byte, 2)
+		_, err = c2.Read(buf)
+		require.NoError(t, err, `Read should succeed`)
+		require.Equal(t, `Al`, string(buf))
+		_, err = c1.Read(buf)
+		require.NoError(t, err, `Read should succeed`)
+		require.Equal(t, `ce`, string(buf))
+	})
+}
@@ -0,0 +1,51 @@
+package byteslice
+
+import (
+	"errors"
+)
+
+// Sentinel errors that are returned, wrapped, by the decoding methods of
+// `Buffer` objects and by the decoders in this package. Use `errors.Is`
+// to determine the cause of a failure.
+//
+// Errors caused by invalid padding match both ErrInvalidPadding and
+// ErrInvalidEncoding when they are returned from a `Buffer` method.
+var (
+	// ErrInvalidEncoding is returned when the input could not be decoded.
+	ErrInvalidEncoding = errors.New(`invalid encoding`)
+
+	// ErrInvalidPadding is returned when the padding of a base64 encoded
+	// input is invalid.
+	ErrInvalidPadding = errors.New(`invalid padding`)
+
+	// ErrMaxLenExceeded is returned when the decoded data would exceed
+	// the limit set by `Buffer.SetMaxDecodeLen`.
+	ErrMaxLenExceeded = errors.New(`maximum decode length exceeded`)
+
+	// ErrValidationFailed is returned when the decoded data is rejected
+	// by the validator set by `Buffer.SetDecodeValidator`.
+	ErrValidationFailed = errors.New(`validation failed`)
+)
+
+// kindError associates an error with one of the sentinel errors, while
+// keeping the original error message and chain intact.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func withKind(kind, err error) error {
+	return &kindError{kind: kind, err: err}
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
@@ -0,0 +1,62 @@
+package byteslice_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("invalid encoding", func(t *testing.T) {
+		var v byteslice.Buffer
+		err := v.DecodeString(`!!!!`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+		require.False(t, errors.Is(err, byteslice.ErrInvalidPadding), `error should not match ErrInvalidPadding`)
+
+		err = json.Unmarshal([]byte(`"!!!!"`), &v)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error from json.Unmarshal should match ErrInvalidEncoding`)
+	})
+	t.Run("invalid encoding (stream)", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Decoder(base64.StdEncoding)
+		_, err := v.DecodeFrom(strings.NewReader(`!!!!`))
+		require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+	})
+	t.Run("invalid padding", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Decoder(byteslice.NewHeuristicDecoder().StrictPadding(true))
+		err := v.DecodeString(`QWxpY2U===`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidPadding), `error should match ErrInvalidPadding`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+
+		err = v.DecodeString(`====`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidPadding), `error should match ErrInvalidPadding`)
+	})
+	t.Run("max length exceeded", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetMaxDecodeLen(4)
+		err := v.DecodeString(`QWxpY2U=`)
+		require.True(t, errors.Is(err, byteslice.ErrMaxLenExceeded), `error should match ErrMaxLenExceeded`)
+		require.False(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should not match ErrInvalidEncoding`)
+
+		// decoders whose output length can not be computed up front
+		v.SetB64Decoder(byteslice.HexDecoder)
+		err = v.DecodeString(`416c696365`)
+		require.True(t, errors.Is(err, byteslice.ErrMaxLenExceeded), `error should match ErrMaxLenExceeded`)
+	})
+	t.Run("validation failed", func(t *testing.T) {
+		cause := fmt.Errorf(`not allowed`)
+
+		var v byteslice.Buffer
+		v.SetDecodeValidator(func([]byte) error { return cause })
+		err := v.DecodeString(`QWxpY2U=`)
+		require.True(t, errors.Is(err, byteslice.ErrValidationFailed), `error should match ErrValidationFailed`)
+		require.True(t, errors.Is(err, cause), `error should match the validator's error`)
+	})
+}
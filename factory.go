@@ -0,0 +1,26 @@
+package byteslice
+
+// Factory creates `Buffer` objects that are preconfigured with a fixed
+// B64Encoder and B64Decoder. It is safe to use a Factory from multiple
+// goroutines.
+type Factory struct {
+	encoder B64Encoder
+	decoder B64Decoder
+}
+
+// NewFactory creates a new `Factory` object. Either `enc` or `dec` may
+// be nil, in which case the `Buffer` objects use the global encoder or
+// decoder, respectively.
+func NewFactory(enc B64Encoder, dec B64Decoder) *Factory {
+	return &Factory{encoder: enc, decoder: dec}
+}
+
+// New creates a new `Buffer` object in the same way as `byteslice.New()`,
+// associated with the encoder and decoder of the factory. The data is
+// copied, unless `WithRawCopy(false)` is specified. `options` are applied
+// after the factory's settings, so they may be used to override them.
+func (f *Factory) New(data []byte, options ...Option) *Buffer {
+	opts := make([]Option, 0, 2+len(options))
+	opts = append(opts, WithB64Encoder(f.encoder), WithB64Decoder(f.decoder))
+	return New(data, append(opts, options...)...)
+}
@@ -0,0 +1,36 @@
+package byteslice_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactory(t *testing.T) {
+	f := byteslice.NewFactory(base64.RawURLEncoding, base64.RawURLEncoding)
+
+	v1 := f.New([]byte{0xfb, 0xff, 0xfe})
+	v2 := f.New([]byte{0xfb, 0xff})
+
+	buf, err := json.Marshal([]*byteslice.Buffer{v1, v2})
+	require.NoError(t, err, `json.Marshal should succeed`)
+	require.Equal(t, `["-__-","-_8"]`, string(buf))
+
+	v3 := f.New(nil)
+	require.NoError(t, json.Unmarshal([]byte(`"-_8"`), v3), `json.Unmarshal should succeed`)
+	require.Equal(t, []byte{0xfb, 0xff}, v3.Bytes())
+	require.Equal(t, base64.RawURLEncoding, v3.B64Decoder())
+
+	t.Run("override", func(t *testing.T) {
+		v := f.New([]byte{0xfb, 0xff}, byteslice.WithB64Encoder(byteslice.HexEncoder))
+		require.Equal(t, `fbff`, v.EncodeToString())
+	})
+	t.Run("nil encoder", func(t *testing.T) {
+		v := byteslice.NewFactory(nil, byteslice.HexDecoder).New([]byte{0xfb, 0xff})
+		require.Equal(t, byteslice.GlobalB64Encoder(), v.B64Encoder())
+		require.Equal(t, byteslice.HexDecoder, v.B64Decoder())
+	})
+}
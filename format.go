@@ -0,0 +1,55 @@
+package byteslice
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Format implements `fmt.Formatter`. The following verbs are supported:
+//
+//   - %s, %v: the base64 encoded form, as returned by EncodeToString()
+//   - %q: the base64 encoded form, double-quoted
+//   - %x, %X: the raw bytes in lower or upper case hexadecimal
+//   - %#v: a Go-syntax representation, such as `byteslice.Buffer{[]byte{0x41}}`
+//
+// Flags, width and precision are honored as they are for strings
+// and byte slices.
+//
+// This method has a value receiver so that `Buffer` values, such as
+// struct fields, are formatted using it, and not just pointers. A nil
+// `*Buffer` is printed as `<nil>` by the "fmt" package.
+func (b Buffer) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			fmt.Fprintf(f, "byteslice.Buffer{%#v}", b.Bytes())
+			return
+		}
+		fmt.Fprintf(f, formatDirective(f, 's'), b.EncodeToString())
+	case 's', 'q':
+		fmt.Fprintf(f, formatDirective(f, verb), b.EncodeToString())
+	case 'x', 'X':
+		fmt.Fprintf(f, formatDirective(f, verb), b.Bytes())
+	default:
+		fmt.Fprintf(f, "%%!%c(byteslice.Buffer=%s)", verb, b.EncodeToString())
+	}
+}
+
+// formatDirective reconstructs the formatting directive described by `f`
+// and `verb`, so that it can be passed on to fmt.Fprintf
+func formatDirective(f fmt.State, verb rune) string {
+	directive := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			directive = append(directive, byte(flag))
+		}
+	}
+	if width, ok := f.Width(); ok {
+		directive = strconv.AppendInt(directive, int64(width), 10)
+	}
+	if prec, ok := f.Precision(); ok {
+		directive = append(directive, '.')
+		directive = strconv.AppendInt(directive, int64(prec), 10)
+	}
+	return string(append(directive, byte(verb)))
+}
@@ -2,10 +2,14 @@ module github.com/lestrrat-go/byteslice
 
 go 1.19
 
-require github.com/stretchr/testify v1.8.0
+require (
+	github.com/fxamacker/cbor/v2 v2.5.0
+	github.com/stretchr/testify v1.8.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 )
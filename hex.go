@@ -0,0 +1,47 @@
+package byteslice
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// HexEncoder is a B64Encoder that encodes `[]byte` into a lowercase
+// hexadecimal string using "encoding/hex".
+//
+// Despite the name of the interface, any object with an `EncodeToString`
+// method may be used, which allows `byteslice.Buffer` to be used
+// for hex encoded fields as well.
+var HexEncoder B64Encoder = hexCodec{}
+
+// HexDecoder is a B64Decoder that decodes hexadecimal strings
+// using "encoding/hex". Both lowercase and uppercase digits are accepted.
+var HexDecoder B64Decoder = hexCodec{}
+
+type hexCodec struct{}
+
+func (hexCodec) EncodeToString(src []byte) string {
+	return hex.EncodeToString(src)
+}
+
+func (hexCodec) DecodeString(src string) ([]byte, error) {
+	return hex.DecodeString(src)
+}
+
+// HexUpperEncoder is a B64Encoder that encodes `[]byte` into an uppercase
+// hexadecimal string.
+var HexUpperEncoder B64Encoder = hexUpperCodec{}
+
+// HexUpperDecoder is a B64Decoder that decodes hexadecimal strings. It is
+// provided for symmetry with HexUpperEncoder, and like HexDecoder, accepts
+// lowercase, uppercase, and mixed case digits.
+var HexUpperDecoder B64Decoder = hexUpperCodec{}
+
+type hexUpperCodec struct{}
+
+func (hexUpperCodec) EncodeToString(src []byte) string {
+	return strings.ToUpper(hex.EncodeToString(src))
+}
+
+func (hexUpperCodec) DecodeString(src string) ([]byte, error) {
+	return hex.DecodeString(src)
+}
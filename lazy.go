@@ -0,0 +1,107 @@
+package byteslice
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LazyBuffer is like `Buffer`, but defers decoding. `UnmarshalJSON` only
+// stores the encoded string, which is decoded on the first call to
+// `Bytes()` or `BytesErr()`. The result, including any error, is cached.
+// This avoids the cost of decoding fields that are never accessed.
+//
+// `MarshalJSON` always emits the original encoded string as is, whether
+// or not the data has been accessed, so LazyBuffer can also be used to
+// pass data through without decoding it at all.
+//
+// It is safe to use the zero value of the `LazyBuffer` object, but like
+// `Buffer`, it is not synchronized.
+type LazyBuffer struct {
+	raw     string
+	null    bool
+	decoder B64Decoder
+
+	decoded bool
+	data    []byte
+	err     error
+}
+
+// SetB64Decoder assigns a B64Decoder for this object, which is used
+// when the data is decoded. If not set, the global decoder at the time
+// of decoding is used.
+func (b *LazyBuffer) SetB64Decoder(dec B64Decoder) *LazyBuffer {
+	b.decoder = dec
+	return b
+}
+
+// UnmarshalJSON implements `"encoding/json".Unmarshaler`. The JSON string
+// is stored without being decoded, and any previously decoded data
+// is discarded. A JSON `null` results in `nil` data.
+func (b *LazyBuffer) UnmarshalJSON(data []byte) error {
+	if b == nil {
+		return fmt.Errorf(`nil byteslice.LazyBuffer`)
+	}
+
+	if string(data) == `null` {
+		*b = LazyBuffer{null: true, decoder: b.decoder}
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf(`failed to unmarshal data to byteslice.LazyBuffer: %w`, err)
+	}
+	*b = LazyBuffer{raw: raw, decoder: b.decoder}
+	return nil
+}
+
+// MarshalJSON implements `"encoding/json".Marshaler`, and emits the
+// original encoded string. As a LazyBuffer can not be modified, the
+// string always matches the data, and it is never re-encoded. The string
+// is not validated, so it is emitted as is even if it can not be decoded,
+// regardless of whether the data has been accessed.
+func (b LazyBuffer) MarshalJSON() ([]byte, error) {
+	if b.null {
+		return []byte(`null`), nil
+	}
+	return json.Marshal(b.raw)
+}
+
+// Raw returns the encoded string as it was unmarshaled.
+func (b *LazyBuffer) Raw() string {
+	if b == nil {
+		return ""
+	}
+	return b.raw
+}
+
+// Bytes returns the decoded data, decoding it first if necessary.
+// nil is returned if decoding fails; use `BytesErr()` to find out why.
+func (b *LazyBuffer) Bytes() []byte {
+	data, _ := b.BytesErr()
+	return data
+}
+
+// BytesErr returns the decoded data, decoding it first if necessary,
+// along with the error that occurred during decoding, if any.
+//
+// As with `Buffer.Bytes()`, the returned `[]byte` is not a copy.
+func (b *LazyBuffer) BytesErr() ([]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+	if b.null {
+		return nil, nil
+	}
+	if !b.decoded {
+		var v Buffer
+		v.SetB64Decoder(b.decoder)
+		b.err = v.DecodeString(b.raw)
+		b.data = v.data
+		b.decoded = true
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.data, nil
+}
@@ -0,0 +1,86 @@
+package byteslice_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyBuffer(t *testing.T) {
+	var count int
+	counting := byteslice.B64DecoderFunc(func(src string) ([]byte, error) {
+		count++
+		return byteslice.HeuristicB64Decoder.DecodeString(src)
+	})
+
+	t.Run("deferred decode", func(t *testing.T) {
+		count = 0
+
+		var v struct {
+			Used   byteslice.LazyBuffer `json:"used"`
+			Unused byteslice.LazyBuffer `json:"unused"`
+		}
+		v.Used.SetB64Decoder(counting)
+		v.Unused.SetB64Decoder(counting)
+		require.NoError(t, json.Unmarshal([]byte(`{"used":"QWxpY2U=","unused":"Qm9i"}`), &v), `json.Unmarshal should succeed`)
+		require.Equal(t, 0, count, `nothing should be decoded by json.Unmarshal`)
+		require.Equal(t, `QWxpY2U=`, v.Used.Raw())
+
+		require.Equal(t, []byte(`Alice`), v.Used.Bytes())
+		require.Equal(t, 1, count, `only the accessed field should be decoded`)
+
+		data, err := v.Used.BytesErr()
+		require.NoError(t, err, `BytesErr should succeed`)
+		require.Equal(t, []byte(`Alice`), data)
+		require.Equal(t, 1, count, `the result should be cached`)
+	})
+	t.Run("error on access", func(t *testing.T) {
+		count = 0
+
+		var v byteslice.LazyBuffer
+		v.SetB64Decoder(counting)
+		require.NoError(t, json.Unmarshal([]byte(`"!!!!"`), &v), `json.Unmarshal should succeed`)
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed before access`)
+		require.Equal(t, `"!!!!"`, string(buf))
+
+		data, err := v.BytesErr()
+		require.Error(t, err, `BytesErr should fail`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+		require.Nil(t, data)
+		require.Nil(t, v.Bytes())
+		require.Equal(t, 1, count, `the error should be cached`)
+
+		buf, err = json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed after access`)
+		require.Equal(t, `"!!!!"`, string(buf), `accessing the data should not change how it is serialized`)
+	})
+	t.Run("pass through", func(t *testing.T) {
+		var v byteslice.LazyBuffer
+		require.NoError(t, json.Unmarshal([]byte(`"-__-"`), &v), `json.Unmarshal should succeed`)
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"-__-"`, string(buf), `undecoded data should be emitted as is`)
+
+		require.Equal(t, []byte{0xfb, 0xff, 0xfe}, v.Bytes())
+		buf, err = json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"-__-"`, string(buf), `accessing the data should not change how it is serialized`)
+	})
+	t.Run("null", func(t *testing.T) {
+		var v byteslice.LazyBuffer
+		require.NoError(t, json.Unmarshal([]byte(`null`), &v), `json.Unmarshal should succeed`)
+		data, err := v.BytesErr()
+		require.NoError(t, err, `BytesErr should succeed`)
+		require.Nil(t, data)
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `null`, string(buf))
+	})
+}
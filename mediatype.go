@@ -0,0 +1,41 @@
+package byteslice
+
+import (
+	"mime"
+	"strings"
+)
+
+// mediaTypeEncodings maps media types that imply an encoding to the
+// name of that encoding, as used by ParsePrefixed
+var mediaTypeEncodings = map[string]string{
+	"application/base64":    "base64",
+	"application/base64url": "base64url",
+}
+
+// DecoderForMediaType returns a B64Decoder suitable for content of the
+// media type `mt`, such as the value of a Content-Type header.
+//
+// The encoding is determined by the "encoding" parameter, if present,
+// as in "application/octet-stream;encoding=base64url". Otherwise,
+// the media types "application/base64" and "application/base64url" are
+// recognized. The supported encodings are the same as those accepted by
+// ParsePrefixed: "base64", "base64url", "hex", and "base32". Padding
+// is optional for all base64 and base32 variants.
+//
+// The last return value is false if `mt` can not be parsed, or if it
+// does not specify a supported encoding.
+func DecoderForMediaType(mt string) (B64Decoder, bool) {
+	typ, params, err := mime.ParseMediaType(mt)
+	if err != nil {
+		return nil, false
+	}
+
+	name, ok := params["encoding"]
+	if !ok {
+		name, ok = mediaTypeEncodings[typ]
+		if !ok {
+			return nil, false
+		}
+	}
+	return prefixedDecoder(strings.ToLower(name))
+}
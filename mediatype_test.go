@@ -0,0 +1,43 @@
+package byteslice_test
+
+import (
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoderForMediaType(t *testing.T) {
+	payload := []byte{0xfb, 0xff, 0xfe, 'A'}
+	testcases := []struct {
+		Name      string
+		MediaType string
+		Source    string
+		Error     bool
+	}{
+		{Name: "application/base64", MediaType: `application/base64`, Source: `+//+QQ==`},
+		{Name: "application/base64url", MediaType: `application/base64url`, Source: `-__-QQ`},
+		{Name: "encoding parameter", MediaType: `application/octet-stream;encoding=base64url`, Source: `-__-QQ==`},
+		{Name: "encoding parameter with spaces and case", MediaType: `Application/Octet-Stream; Encoding="HEX"`, Source: `fbfffe41`},
+		{Name: "encoding parameter overrides type", MediaType: `application/base64; encoding=base32`, Source: `7P774QI=`},
+		{Name: "no encoding", MediaType: `application/octet-stream`, Error: true},
+		{Name: "unknown encoding", MediaType: `application/octet-stream;encoding=base58`, Error: true},
+		{Name: "invalid media type", MediaType: `;;;`, Error: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			dec, ok := byteslice.DecoderForMediaType(tc.MediaType)
+			if tc.Error {
+				require.False(t, ok, `DecoderForMediaType should fail`)
+				return
+			}
+			require.True(t, ok, `DecoderForMediaType should succeed`)
+
+			var v byteslice.Buffer
+			v.SetB64Decoder(dec)
+			require.NoError(t, v.DecodeString(tc.Source), `DecodeString should succeed`)
+			require.Equal(t, payload, v.Bytes())
+		})
+	}
+}
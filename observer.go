@@ -0,0 +1,79 @@
+package byteslice
+
+import (
+	"encoding/base64"
+	"sync/atomic"
+)
+
+// decodeObserverFunc is the type stored in globalDecodeObserver. atomic.Value
+// requires all stored values to be of the same concrete type, so a nil
+// observer is stored as a nil decodeObserverFunc
+type decodeObserverFunc func(encodedLen, decodedLen int, enc string)
+
+// globalDecodeObserver is accessed atomically, instead of through globalMu,
+// so that checking for an observer on every decode stays cheap
+var globalDecodeObserver atomic.Value
+
+// SetDecodeObserver registers a function that is called after each
+// successful decode, such as in `UnmarshalJSON`, `UnmarshalText`,
+// `DecodeString`, `DecodeFrom`, or `ParsePrefixed`. It can be used to
+// record metrics, or for tracing. Passing nil removes the observer.
+//
+// `encodedLen` is the length of the encoded string (excluding JSON quotes),
+// or the number of bytes read by `DecodeFrom`. `decodedLen` is the length
+// of the decoded data, and `enc` is the name of the `*base64.Encoding`
+// that was used: "base64", "base64url",
+// "base64-raw", or "base64url-raw". Strict variants (see `NewStrictDecoder`)
+// are reported under the same names, and encodings with a padding character
+// other than '=' are reported as "base64" or "base64url". `enc` is the
+// empty string if the encoding is unknown: that is, if it could not be
+// determined (see `LastDecodeEncoding()`), as is the case for non-base64
+// decoders such as HexDecoder, or if it uses a custom alphabet.
+//
+// When `UnmarshalJSON` accepts a JSON array of integers, `enc` is "array",
+// and `encodedLen` is the length of the JSON array.
+//
+// The observer is called synchronously, from the goroutine that performed
+// the decode, and may be called concurrently from multiple goroutines.
+func SetDecodeObserver(fn func(encodedLen, decodedLen int, enc string)) {
+	globalDecodeObserver.Store(decodeObserverFunc(fn))
+}
+
+func notifyDecodeObserver(encodedLen, decodedLen int, enc *base64.Encoding) {
+	if fn := decodeObserver(); fn != nil {
+		fn(encodedLen, decodedLen, encodingName(enc))
+	}
+}
+
+func decodeObserver() decodeObserverFunc {
+	fn, _ := globalDecodeObserver.Load().(decodeObserverFunc)
+	return fn
+}
+
+func encodingName(enc *base64.Encoding) string {
+	switch enc {
+	case nil:
+		return ""
+	case base64.StdEncoding:
+		return "base64"
+	case base64.URLEncoding:
+		return "base64url"
+	case base64.RawStdEncoding:
+		return "base64-raw"
+	case base64.RawURLEncoding:
+		return "base64url-raw"
+	}
+
+	isURL, ok := encodingAlphabet(enc)
+	if !ok {
+		return ""
+	}
+	name := "base64"
+	if isURL {
+		name = "base64url"
+	}
+	if enc.EncodedLen(1) < 4 {
+		name += "-raw"
+	}
+	return name
+}
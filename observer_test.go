@@ -0,0 +1,118 @@
+package byteslice_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeObserver(t *testing.T) {
+	type observation struct {
+		EncodedLen int
+		DecodedLen int
+		Encoding   string
+	}
+
+	var observed []observation
+	byteslice.SetDecodeObserver(func(encodedLen, decodedLen int, enc string) {
+		observed = append(observed, observation{EncodedLen: encodedLen, DecodedLen: decodedLen, Encoding: enc})
+	})
+	defer byteslice.SetDecodeObserver(nil)
+
+	t.Run("UnmarshalJSON", func(t *testing.T) {
+		observed = nil
+
+		var v byteslice.Buffer
+		require.NoError(t, json.Unmarshal([]byte(`"Pz8/Pz8="`), &v), `json.Unmarshal should succeed`)
+		require.Equal(t, []observation{{EncodedLen: 8, DecodedLen: 5, Encoding: "base64"}}, observed)
+	})
+	t.Run("UnmarshalJSON with escapes", func(t *testing.T) {
+		observed = nil
+
+		var v byteslice.Buffer
+		require.NoError(t, json.Unmarshal([]byte(`"Pz8\/Pz8="`), &v), `json.Unmarshal should succeed`)
+		require.Equal(t, []observation{{EncodedLen: 8, DecodedLen: 5, Encoding: "base64"}}, observed)
+	})
+	t.Run("DecodeString", func(t *testing.T) {
+		observed = nil
+
+		var v byteslice.Buffer
+		require.NoError(t, v.DecodeString(`-__-QWxp`), `DecodeString should succeed`)
+		require.Equal(t, []observation{{EncodedLen: 8, DecodedLen: 6, Encoding: "base64url-raw"}}, observed)
+
+		v.SetB64Decoder(byteslice.HexDecoder)
+		require.NoError(t, v.DecodeString(`414243`), `DecodeString should succeed`)
+		require.Equal(t, observation{EncodedLen: 6, DecodedLen: 3, Encoding: ""}, observed[1])
+	})
+	t.Run("strict and custom padding decoders", func(t *testing.T) {
+		testcases := []struct {
+			Decoder  byteslice.B64Decoder
+			Source   string
+			Expected string
+		}{
+			{Decoder: byteslice.NewStrictDecoder(base64.StdEncoding), Source: `+//+QQ==`, Expected: "base64"},
+			{Decoder: byteslice.NewStrictDecoder(base64.URLEncoding), Source: `-__-QQ==`, Expected: "base64url"},
+			{Decoder: byteslice.NewStrictDecoder(base64.RawStdEncoding), Source: `+//+QQ`, Expected: "base64-raw"},
+			{Decoder: byteslice.NewStrictDecoder(base64.RawURLEncoding), Source: `-__-QQ`, Expected: "base64url-raw"},
+			{Decoder: base64.URLEncoding.WithPadding('.'), Source: `-__-QQ..`, Expected: "base64url"},
+		}
+		for _, tc := range testcases {
+			observed = nil
+
+			var v byteslice.Buffer
+			v.SetB64Decoder(tc.Decoder)
+			require.NoError(t, v.DecodeString(tc.Source), `DecodeString should succeed`)
+			require.Equal(t, []observation{{EncodedLen: len(tc.Source), DecodedLen: 4, Encoding: tc.Expected}}, observed)
+		}
+	})
+	t.Run("custom alphabet", func(t *testing.T) {
+		observed = nil
+
+		_, dec, err := byteslice.NewCustomB64(`ZYXWVUTSRQPONMLKJIHGFEDCBAzyxwvutsrqponmlkjihgfedcba9876543210-_`, '.')
+		require.NoError(t, err, `NewCustomB64 should succeed`)
+
+		var v byteslice.Buffer
+		v.SetB64Decoder(dec)
+		require.NoError(t, v.DecodeString(`JDckB7F.`), `DecodeString should succeed`)
+		require.Equal(t, []observation{{EncodedLen: 8, DecodedLen: 5, Encoding: ""}}, observed, `custom alphabets should be reported as unknown`)
+	})
+	t.Run("DecodeFrom", func(t *testing.T) {
+		for _, dec := range []byteslice.B64Decoder{base64.RawURLEncoding, byteslice.HeuristicB64Decoder} {
+			observed = nil
+
+			var v byteslice.Buffer
+			v.SetB64Decoder(dec)
+			_, err := v.DecodeFrom(strings.NewReader(`-__-QWxp`))
+			require.NoError(t, err, `DecodeFrom should succeed`)
+			require.Equal(t, []observation{{EncodedLen: 8, DecodedLen: 6, Encoding: "base64url-raw"}}, observed, `%T should be observed`, dec)
+		}
+	})
+	t.Run("JSON array", func(t *testing.T) {
+		observed = nil
+
+		var v byteslice.Buffer
+		require.NoError(t, json.Unmarshal([]byte(`[72,105]`), &v), `json.Unmarshal should succeed`)
+		require.Equal(t, []observation{{EncodedLen: 8, DecodedLen: 2, Encoding: "array"}}, observed)
+	})
+	t.Run("not called on failure", func(t *testing.T) {
+		observed = nil
+
+		var v byteslice.Buffer
+		v.SetB64Decoder(base64.StdEncoding)
+		require.Error(t, v.DecodeString(`!!!!`), `DecodeString should fail`)
+		require.Error(t, json.Unmarshal([]byte(`"!!!!"`), &v), `json.Unmarshal should fail`)
+		require.Empty(t, observed)
+	})
+	t.Run("nil disables", func(t *testing.T) {
+		observed = nil
+		byteslice.SetDecodeObserver(nil)
+
+		var v byteslice.Buffer
+		require.NoError(t, v.DecodeString(`QWxpY2U=`), `DecodeString should succeed`)
+		require.Empty(t, observed)
+	})
+}
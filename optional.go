@@ -0,0 +1,26 @@
+package byteslice
+
+// OptionalBuffer is a `[]byte` that is serialized to and from JSON in
+// the same way as `Buffer`, using the global B64Encoder and B64Decoder.
+//
+// Unlike `Buffer`, which is a struct, OptionalBuffer is a slice type,
+// so that struct fields tagged with `json:",omitempty"` are omitted
+// when they are empty. Alternatively, use a `*Buffer` field with
+// `omitempty`, which is omitted when the pointer is nil.
+type OptionalBuffer []byte
+
+// MarshalJSON implements `"encoding/json".Marshaler`
+func (o OptionalBuffer) MarshalJSON() ([]byte, error) {
+	return Buffer{data: o}.MarshalJSON()
+}
+
+// UnmarshalJSON implements `"encoding/json".Unmarshaler`. A JSON `null`
+// sets the value to `nil`.
+func (o *OptionalBuffer) UnmarshalJSON(data []byte) error {
+	var b Buffer
+	if err := b.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*o = b.data
+	return nil
+}
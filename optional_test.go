@@ -0,0 +1,45 @@
+package byteslice_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalBuffer(t *testing.T) {
+	type foo struct {
+		Buffer   byteslice.Buffer         `json:"buffer,omitempty"`
+		Pointer  *byteslice.Buffer        `json:"pointer,omitempty"`
+		Optional byteslice.OptionalBuffer `json:"optional,omitempty"`
+	}
+	t.Run("empty", func(t *testing.T) {
+		buf, err := json.Marshal(foo{Optional: byteslice.OptionalBuffer{}})
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `{"buffer":""}`, string(buf), `only the Buffer field should be serialized`)
+	})
+	t.Run("non-empty", func(t *testing.T) {
+		src := foo{
+			Pointer:  byteslice.New([]byte(`Bob`)),
+			Optional: byteslice.OptionalBuffer(`Alice`),
+		}
+		buf, err := json.Marshal(src)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `{"buffer":"","pointer":"Qm9i","optional":"QWxpY2U="}`, string(buf))
+
+		var dst foo
+		require.NoError(t, json.Unmarshal(buf, &dst), `json.Unmarshal should succeed`)
+		require.Equal(t, byteslice.OptionalBuffer(`Alice`), dst.Optional)
+		require.Equal(t, []byte(`Bob`), dst.Pointer.Bytes())
+	})
+	t.Run("null", func(t *testing.T) {
+		dst := foo{Optional: byteslice.OptionalBuffer(`stale`)}
+		require.NoError(t, json.Unmarshal([]byte(`{"optional":null}`), &dst), `json.Unmarshal should succeed`)
+		require.Nil(t, dst.Optional)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var dst foo
+		require.Error(t, json.Unmarshal([]byte(`{"optional":"!!!!"}`), &dst), `json.Unmarshal should fail`)
+	})
+}
@@ -0,0 +1,45 @@
+package byteslice
+
+// Option is the interface for options that can be passed to New()
+type Option interface {
+	Ident() interface{}
+	Value() interface{}
+}
+
+type option struct {
+	ident interface{}
+	value interface{}
+}
+
+func (o *option) Ident() interface{} {
+	return o.ident
+}
+
+func (o *option) Value() interface{} {
+	return o.value
+}
+
+type identB64Encoder struct{}
+type identB64Decoder struct{}
+type identRawCopy struct{}
+
+// WithB64Encoder specifies the B64Encoder to be associated with
+// the new `Buffer` object. See also `Buffer.SetB64Encoder()`
+func WithB64Encoder(enc B64Encoder) Option {
+	return &option{ident: identB64Encoder{}, value: enc}
+}
+
+// WithB64Decoder specifies the B64Decoder to be associated with
+// the new `Buffer` object. See also `Buffer.SetB64Decoder()`
+func WithB64Decoder(dec B64Decoder) Option {
+	return &option{ident: identB64Decoder{}, value: dec}
+}
+
+// WithRawCopy specifies if the `[]byte` passed to New() should be
+// copied (true), or adopted as the internal `[]byte` as is (false).
+// When adopted, the caller must not modify the `[]byte` afterwards.
+//
+// The default is to copy the data.
+func WithRawCopy(v bool) Option {
+	return &option{ident: identRawCopy{}, value: v}
+}
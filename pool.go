@@ -0,0 +1,52 @@
+package byteslice
+
+import (
+	"sync"
+)
+
+// BufferPool is a pool of `Buffer` objects, backed by a `sync.Pool`.
+// It is safe to use the zero value of the `BufferPool` object, and
+// it is safe to use it from multiple goroutines.
+type BufferPool struct {
+	pool   sync.Pool
+	maxCap int
+}
+
+// SetMaxCap specifies the maximum capacity of `Buffer` objects that are
+// kept in the pool. `Buffer` objects whose `Cap()` exceeds `n` are
+// discarded by `Put`, so that a few large payloads do not cause the pool
+// to hold on to large amounts of memory. A value of 0 or less, which is
+// the default, disables the check.
+//
+// SetMaxCap must be called before the pool is used.
+func (p *BufferPool) SetMaxCap(n int) *BufferPool {
+	p.maxCap = n
+	return p
+}
+
+// Get returns a `Buffer` object from the pool, or creates a new one
+// if the pool is empty. The returned object is always in its zero state
+// (see `Buffer.Reset`), although it may hold capacity from previous use.
+func (p *BufferPool) Get() *Buffer {
+	if b, ok := p.pool.Get().(*Buffer); ok {
+		return b
+	}
+	return &Buffer{}
+}
+
+// Put resets the `Buffer` object and returns it to the pool. If the
+// capacity of the object exceeds the limit set by `SetMaxCap`, it is
+// discarded instead.
+//
+// The caller must not retain or use the `Buffer` object, nor any
+// slice obtained from it via `Bytes()`, after calling Put.
+func (p *BufferPool) Put(b *Buffer) {
+	if b == nil {
+		return
+	}
+	if p.maxCap > 0 && b.Cap() > p.maxCap {
+		return
+	}
+	b.Reset()
+	p.pool.Put(b)
+}
@@ -0,0 +1,73 @@
+package byteslice_test
+
+import (
+	"encoding/base64"
+	"sync"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferPool(t *testing.T) {
+	t.Run("reset on put", func(t *testing.T) {
+		var pool byteslice.BufferPool
+		v := pool.Get()
+		v.SetBytes([]byte(`Alice`))
+		v.SetB64Encoder(base64.RawURLEncoding)
+		pool.Put(v)
+
+		v = pool.Get()
+		require.Equal(t, 0, v.Len(), `Buffer from the pool should be empty`)
+		require.Equal(t, byteslice.GlobalB64Encoder(), v.B64Encoder(), `Buffer from the pool should use the global encoder`)
+	})
+	t.Run("max cap", func(t *testing.T) {
+		var pool byteslice.BufferPool
+		pool.SetMaxCap(64)
+
+		v := pool.Get()
+		v.Grow(128)
+		pool.Put(v)
+		require.Equal(t, 0, pool.Get().Cap(), `oversized Buffer should be discarded`)
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		var pool byteslice.BufferPool
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					v := pool.Get()
+					if v.Len() != 0 {
+						t.Errorf(`Buffer from the pool should be empty`)
+						return
+					}
+					v.AppendString(`Alice`)
+					pool.Put(v)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func BenchmarkBufferPool(b *testing.B) {
+	payload := make([]byte, 256)
+	b.Run("new(Buffer)", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			v := new(byteslice.Buffer)
+			v.SetBytes(payload)
+		}
+	})
+	b.Run("BufferPool", func(b *testing.B) {
+		var pool byteslice.BufferPool
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			v := pool.Get()
+			v.SetBytes(payload)
+			pool.Put(v)
+		}
+	})
+}
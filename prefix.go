@@ -0,0 +1,119 @@
+package byteslice
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// prefixedDecoders lists the prefixes recognized by ParsePrefixed, and
+// the decoders used for each of them
+var prefixedDecoders = []struct {
+	prefix  string
+	decoder B64Decoder
+}{
+	{prefix: "base64:", decoder: paddingAwareDecoder{padded: base64.StdEncoding, raw: base64.RawStdEncoding}},
+	{prefix: "base64url:", decoder: paddingAwareDecoder{padded: base64.URLEncoding, raw: base64.RawURLEncoding}},
+	{prefix: "hex:", decoder: HexDecoder},
+	{prefix: "base32:", decoder: paddingAwareDecoder{padded: Base32StdDecoder, raw: Base32RawStdDecoder}},
+}
+
+// ParsePrefixed decodes a string whose encoding is specified by a prefix,
+// and replaces the contents of the buffer with the result. The following
+// prefixes are recognized:
+//
+//   - "base64:" for the standard base64 encoding, with or without padding
+//   - "base64url:" for the URL-safe base64 encoding, with or without padding
+//   - "hex:" for hexadecimal encoding
+//   - "base32:" for the standard base32 encoding, with or without padding
+//
+// If `s` does not start with any of these prefixes, the entire string
+// is decoded using the default heuristic decoder (HeuristicB64Decoder),
+// regardless of the decoder associated with this object.
+func (b *Buffer) ParsePrefixed(s string) error {
+	if dec, rest, ok := splitPrefixed(s); ok {
+		return b.decodeAndSetStringWith(dec, rest)
+	}
+	return b.decodeAndSetStringWith(HeuristicB64Decoder, s)
+}
+
+// splitPrefixed returns the decoder for the prefix that `s` starts with,
+// and the remainder of `s` after the prefix
+func splitPrefixed(s string) (B64Decoder, string, bool) {
+	for _, p := range prefixedDecoders {
+		if strings.HasPrefix(s, p.prefix) {
+			return p.decoder, s[len(p.prefix):], true
+		}
+	}
+	return nil, s, false
+}
+
+// encoderPrefix returns the prefix that describes the output of `enc`,
+// such that ParsePrefixed can decode it
+func encoderPrefix(enc B64Encoder) (string, bool) {
+	if enc, ok := enc.(*base64.Encoding); ok {
+		return base64Prefix(enc)
+	}
+
+	switch enc {
+	case HexEncoder, HexUpperEncoder:
+		return "hex:", true
+	case Base32StdEncoder, Base32RawStdEncoder:
+		return "base32:", true
+	default:
+		return "", false
+	}
+}
+
+// prefixedDecoder returns the decoder used for the prefix `name`,
+// specified without the trailing colon
+func prefixedDecoder(name string) (B64Decoder, bool) {
+	for _, p := range prefixedDecoders {
+		if p.prefix[:len(p.prefix)-1] == name {
+			return p.decoder, true
+		}
+	}
+	return nil, false
+}
+
+// base64Prefix returns the prefix for `enc`, which may be any variant of
+// the standard or URL-safe encodings, including strict ones, as long as
+// it pads with '=' or does not pad at all
+func base64Prefix(enc *base64.Encoding) (string, bool) {
+	switch enc {
+	case base64.StdEncoding, base64.RawStdEncoding:
+		return "base64:", true
+	case base64.URLEncoding, base64.RawURLEncoding:
+		return "base64url:", true
+	}
+
+	isURL, ok := encodingAlphabet(enc)
+	if !ok {
+		return "", false
+	}
+	if padding := encodingPadding(enc); padding != '=' && padding != base64.NoPadding {
+		return "", false
+	}
+	if isURL {
+		return "base64url:", true
+	}
+	return "base64:", true
+}
+
+// paddingAwareDecoder decodes using `padded` if the input ends with
+// a padding character, and `raw` otherwise
+type paddingAwareDecoder struct {
+	padded B64Decoder
+	raw    B64Decoder
+}
+
+func (d paddingAwareDecoder) DecodeString(src string) ([]byte, error) {
+	buf, _, err := d.decodeStringDetect(src)
+	return buf, err
+}
+
+func (d paddingAwareDecoder) decodeStringDetect(src string) ([]byte, *base64.Encoding, error) {
+	if strings.HasSuffix(src, "=") {
+		return decodeString(d.padded, src)
+	}
+	return decodeString(d.raw, src)
+}
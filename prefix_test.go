@@ -0,0 +1,122 @@
+package byteslice_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrefixed(t *testing.T) {
+	payload := []byte{0xfb, 0xff, 0xfe, 'A'}
+	testcases := []struct {
+		Name   string
+		Source string
+		Error  bool
+	}{
+		{Name: "base64", Source: `base64:+//+QQ==`},
+		{Name: "base64 without padding", Source: `base64:+//+QQ`},
+		{Name: "base64url", Source: `base64url:-__-QQ==`},
+		{Name: "base64url without padding", Source: `base64url:-__-QQ`},
+		{Name: "hex", Source: `hex:fbfffe41`},
+		{Name: "base32", Source: `base32:7P774QI=`},
+		{Name: "base32 without padding", Source: `base32:7P774QI`},
+		{Name: "no prefix", Source: `-__-QQ`},
+		{Name: "wrong alphabet for prefix", Source: `base64:-__-QQ`, Error: true},
+		{Name: "unknown prefix", Source: `base58:2g`, Error: true},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			var v byteslice.Buffer
+			err := v.ParsePrefixed(tc.Source)
+			if tc.Error {
+				require.Error(t, err, `ParsePrefixed should fail`)
+				return
+			}
+			require.NoError(t, err, `ParsePrefixed should succeed`)
+			require.Equal(t, payload, v.Bytes())
+		})
+	}
+}
+
+func TestSelfDescribing(t *testing.T) {
+	payload := []byte{0xfb, 0xff, 0xfe, 'A'}
+	testcases := []struct {
+		Name     string
+		Encoder  byteslice.B64Encoder
+		Expected string
+	}{
+		{Name: "base64", Encoder: base64.StdEncoding, Expected: `"base64:+//+QQ=="`},
+		{Name: "base64 without padding", Encoder: base64.RawStdEncoding, Expected: `"base64:+//+QQ"`},
+		{Name: "base64url", Encoder: base64.URLEncoding, Expected: `"base64url:-__-QQ=="`},
+		{Name: "base64url without padding", Encoder: base64.RawURLEncoding, Expected: `"base64url:-__-QQ"`},
+		{Name: "hex", Encoder: byteslice.HexEncoder, Expected: `"hex:fbfffe41"`},
+		{Name: "uppercase hex", Encoder: byteslice.HexUpperEncoder, Expected: `"hex:FBFFFE41"`},
+		{Name: "base32", Encoder: byteslice.Base32StdEncoder, Expected: `"base32:7P774QI="`},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(payload, byteslice.WithB64Encoder(tc.Encoder))
+			v.SetSelfDescribing(true)
+
+			buf, err := json.Marshal(v)
+			require.NoError(t, err, `json.Marshal should succeed`)
+			require.Equal(t, tc.Expected, string(buf))
+
+			// the decoder associated with the buffer is ignored in favor
+			// of the one named by the prefix
+			var rt byteslice.Buffer
+			rt.SetSelfDescribing(true)
+			rt.SetB64Decoder(base64.StdEncoding)
+			require.NoError(t, json.Unmarshal(buf, &rt), `json.Unmarshal should succeed`)
+			require.Equal(t, payload, rt.Bytes())
+		})
+	}
+	t.Run("round trip with strict decoder", func(t *testing.T) {
+		expected := map[*base64.Encoding]string{
+			base64.StdEncoding:    `"base64:+//+QQ=="`,
+			base64.RawURLEncoding: `"base64url:-__-QQ"`,
+		}
+		for enc, encoded := range expected {
+			var v byteslice.Buffer
+			v.SetSelfDescribing(true).SetRoundTripEncoding(true)
+			v.SetB64Decoder(byteslice.NewStrictDecoder(enc))
+			require.NoError(t, v.DecodeString(enc.EncodeToString(payload)), `DecodeString should succeed`)
+
+			buf, err := json.Marshal(v)
+			require.NoError(t, err, `json.Marshal should succeed`)
+			require.Equal(t, encoded, string(buf))
+
+			var rt byteslice.Buffer
+			rt.SetSelfDescribing(true)
+			require.NoError(t, json.Unmarshal(buf, &rt), `json.Unmarshal should succeed`)
+			require.Equal(t, payload, rt.Bytes())
+		}
+	})
+	t.Run("custom padding", func(t *testing.T) {
+		v := byteslice.New(payload, byteslice.WithB64Encoder(base64.StdEncoding.WithPadding('.')))
+		v.SetSelfDescribing(true)
+		_, err := json.Marshal(v)
+		require.Error(t, err, `json.Marshal should fail`)
+	})
+	t.Run("unmarshal without prefix", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetSelfDescribing(true)
+		require.NoError(t, json.Unmarshal([]byte(`"-__-QQ"`), &v), `json.Unmarshal should succeed`)
+		require.Equal(t, payload, v.Bytes())
+	})
+	t.Run("prefix is not honored when disabled", func(t *testing.T) {
+		var v byteslice.Buffer
+		require.Error(t, json.Unmarshal([]byte(`"hex:fbfffe41"`), &v), `json.Unmarshal should fail`)
+	})
+	t.Run("unknown encoder", func(t *testing.T) {
+		v := byteslice.New(payload, byteslice.WithB64Encoder(byteslice.Base32HexEncoder))
+		v.SetSelfDescribing(true)
+		_, err := json.Marshal(v)
+		require.Error(t, err, `json.Marshal should fail`)
+	})
+}
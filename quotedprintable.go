@@ -0,0 +1,44 @@
+package byteslice
+
+import (
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+)
+
+// QuotedPrintableEncoder is a B64Encoder that encodes `[]byte` into a
+// quoted-printable string (RFC 2045) using "mime/quotedprintable".
+// The input is treated as binary data, so line breaks in the payload are
+// escaped rather than converted to CRLF. Long lines are split using
+// soft line breaks.
+var QuotedPrintableEncoder B64Encoder = quotedPrintableCodec{}
+
+// QuotedPrintableDecoder is a B64Decoder that decodes quoted-printable
+// strings using "mime/quotedprintable". Like the underlying reader,
+// malformed escape sequences such as "=ZZ" are passed through as-is,
+// but unescaped control characters are rejected.
+var QuotedPrintableDecoder B64Decoder = quotedPrintableCodec{}
+
+type quotedPrintableCodec struct{}
+
+func (quotedPrintableCodec) EncodeToString(src []byte) string {
+	var sb strings.Builder
+	w := quotedprintable.NewWriter(&sb)
+	w.Binary = true
+	// Writing to a strings.Builder never fails
+	_, _ = w.Write(src)
+	_ = w.Close()
+	return sb.String()
+}
+
+func (quotedPrintableCodec) DecodeString(src string) ([]byte, error) {
+	buf, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(src)))
+	if err != nil {
+		return nil, fmt.Errorf(`failed to decode quoted-printable string: %w`, err)
+	}
+	if buf == nil {
+		buf = []byte{}
+	}
+	return buf, nil
+}
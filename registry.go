@@ -0,0 +1,60 @@
+package byteslice
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sync"
+)
+
+type namedCodec struct {
+	encoder B64Encoder
+	decoder B64Decoder
+}
+
+var codecMu sync.RWMutex
+var codecs = make(map[string]namedCodec)
+
+// RegisterCodec registers a B64Encoder and B64Decoder pair under `name`,
+// so that they can be looked up via `Codec()` or `Buffer.SetCodec()`.
+// Registering a codec under an existing name replaces the previous one.
+//
+// The following names are registered by default: "std", "rawstd",
+// "url", "rawurl", "hex", and "base32".
+func RegisterCodec(name string, enc B64Encoder, dec B64Decoder) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+
+	codecs[name] = namedCodec{encoder: enc, decoder: dec}
+}
+
+// Codec returns the B64Encoder and B64Decoder pair registered under `name`.
+// The last return value is false if no codec has been registered
+// under the given name.
+func Codec(name string) (B64Encoder, B64Decoder, bool) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+
+	c, ok := codecs[name]
+	return c.encoder, c.decoder, ok
+}
+
+// SetCodec assigns the B64Encoder and B64Decoder pair registered under
+// `name` via `RegisterCodec()` to this object.
+func (b *Buffer) SetCodec(name string) error {
+	enc, dec, ok := Codec(name)
+	if !ok {
+		return fmt.Errorf(`failed to set codec for byteslice.Buffer: codec %q is not registered`, name)
+	}
+	b.SetB64Encoder(enc)
+	b.SetB64Decoder(dec)
+	return nil
+}
+
+func init() {
+	RegisterCodec("std", base64.StdEncoding, base64.StdEncoding)
+	RegisterCodec("rawstd", base64.RawStdEncoding, base64.RawStdEncoding)
+	RegisterCodec("url", base64.URLEncoding, base64.URLEncoding)
+	RegisterCodec("rawurl", base64.RawURLEncoding, base64.RawURLEncoding)
+	RegisterCodec("hex", HexEncoder, HexDecoder)
+	RegisterCodec("base32", Base32StdEncoder, Base32StdDecoder)
+}
@@ -0,0 +1,52 @@
+package byteslice_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecRegistry(t *testing.T) {
+	t.Run("builtin", func(t *testing.T) {
+		for _, name := range []string{"std", "rawstd", "url", "rawurl", "hex", "base32"} {
+			enc, dec, ok := byteslice.Codec(name)
+			require.True(t, ok, `codec %q should be registered`, name)
+			require.NotNil(t, enc, `encoder for %q should not be nil`, name)
+			require.NotNil(t, dec, `decoder for %q should not be nil`, name)
+		}
+
+		var v byteslice.Buffer
+		require.NoError(t, v.SetCodec("rawurl"), `SetCodec should succeed`)
+		require.Equal(t, base64.RawURLEncoding, v.B64Encoder())
+		require.Equal(t, base64.RawURLEncoding, v.B64Decoder())
+	})
+	t.Run("custom", func(t *testing.T) {
+		upper := byteslice.B64EncoderFunc(func(src []byte) string {
+			return strings.ToUpper(byteslice.HexEncoder.EncodeToString(src))
+		})
+		byteslice.RegisterCodec("test-upperhex", upper, byteslice.HexDecoder)
+
+		v := byteslice.New([]byte{0xde, 0xad, 0xbe, 0xef})
+		require.NoError(t, v.SetCodec("test-upperhex"), `SetCodec should succeed`)
+
+		buf, err := json.Marshal(v)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.Equal(t, `"DEADBEEF"`, string(buf))
+
+		var rt byteslice.Buffer
+		require.NoError(t, rt.SetCodec("test-upperhex"), `SetCodec should succeed`)
+		require.NoError(t, json.Unmarshal(buf, &rt), `json.Unmarshal should succeed`)
+		require.Equal(t, v.Bytes(), rt.Bytes())
+	})
+	t.Run("unknown", func(t *testing.T) {
+		_, _, ok := byteslice.Codec("does-not-exist")
+		require.False(t, ok, `codec should not be registered`)
+
+		var v byteslice.Buffer
+		require.Error(t, v.SetCodec("does-not-exist"), `SetCodec should fail`)
+	})
+}
@@ -0,0 +1,257 @@
+package byteslice
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DecodeFrom reads base64 encoded data from `r` until EOF, and replaces
+// the contents of the buffer with the decoded result. It returns the
+// number of bytes read from `r`.
+//
+// If the B64Decoder object associated with this object (or the global
+// one, if not specified) is a `*base64.Encoding` object, the data is
+// decoded while it is being read, so the encoded form is never held in
+// memory in its entirety. Otherwise, the entire input is read first,
+// and then decoded using the decoder's `DecodeString` method.
+//
+// This method is deliberately not named `ReadFrom`: `Buffer` implements
+// `io.Writer` by appending raw bytes, and `io.Copy` would pick up an
+// `io.ReaderFrom` implementation in its place.
+func (b *Buffer) DecodeFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+
+	if enc, ok := b.B64Decoder().(*base64.Encoding); ok {
+		var dec io.Reader = base64.NewDecoder(enc, cr)
+		if b.maxDecodeLen > 0 {
+			// read one extra byte, so that setDecoded can detect oversized inputs
+			dec = io.LimitReader(dec, int64(b.maxDecodeLen)+1)
+		}
+		buf, err := io.ReadAll(dec)
+		if err != nil {
+			var cerr base64.CorruptInputError
+			if errors.As(err, &cerr) {
+				err = withKind(ErrInvalidEncoding, err)
+			}
+			return cr.n, fmt.Errorf(`failed to decode stream for byteslice.Buffer: %w`, err)
+		}
+		if err := b.setDecoded(buf, enc); err != nil {
+			return cr.n, err
+		}
+		notifyDecodeObserver(int(cr.n), len(buf), enc)
+		return cr.n, nil
+	}
+
+	encoded, err := io.ReadAll(cr)
+	if err != nil {
+		return cr.n, fmt.Errorf(`failed to read stream for byteslice.Buffer: %w`, err)
+	}
+	if err := b.decodeAndSetString(string(encoded)); err != nil {
+		return cr.n, err
+	}
+	return cr.n, nil
+}
+
+// WriteTo implements `io.WriterTo`, and writes the base64 encoded form
+// of the buffer to `w`. It returns the number of bytes written to `w`.
+//
+// If the B64Encoder object associated with this object (or the global
+// one, if not specified) is a `*base64.Encoding` object, the data is
+// encoded in small chunks, so the encoded form is never held in memory
+// in its entirety. Otherwise, the data is encoded using the encoder's
+// `EncodeToString` method, and then written.
+//
+// The data is encoded directly from the internal `[]byte` without
+// taking a snapshot, so the buffer must not be modified until WriteTo
+// returns. Nothing is written for a nil `*Buffer`.
+func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	cw := &countingWriter{w: w}
+
+	switch enc := b.B64Encoder().(type) {
+	case *base64.Encoding:
+		e := base64.NewEncoder(enc, cw)
+		if _, err := e.Write(b.data); err != nil {
+			return cw.n, fmt.Errorf(`failed to write encoded byteslice.Buffer: %w`, err)
+		}
+		if err := e.Close(); err != nil {
+			return cw.n, fmt.Errorf(`failed to write encoded byteslice.Buffer: %w`, err)
+		}
+	default:
+		if _, err := io.WriteString(cw, enc.EncodeToString(b.data)); err != nil {
+			return cw.n, fmt.Errorf(`failed to write encoded byteslice.Buffer: %w`, err)
+		}
+	}
+	return cw.n, nil
+}
+
+// WriteEncodedTo writes the base64 encoded form of the buffer to `sb`,
+// and returns the number of bytes written. It is the `strings.Builder`
+// counterpart of `WriteTo`, for building large strings by hand.
+//
+// If the B64Encoder object associated with this object (or the global
+// one, if not specified) is a `*base64.Encoding` object, the data is
+// encoded in small chunks directly into `sb`, without allocating an
+// intermediate string. Otherwise, the data is encoded using the encoder's
+// `EncodeToString` method, and then written.
+//
+// Writing to a `strings.Builder` never fails, but an error is returned
+// for symmetry with `WriteTo`.
+func (b *Buffer) WriteEncodedTo(sb *strings.Builder) (int, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	switch enc := b.B64Encoder().(type) {
+	case *base64.Encoding:
+		sb.Grow(enc.EncodedLen(len(b.data)))
+
+		// Each chunk is a multiple of 3 bytes, so that only the last
+		// chunk may require padding
+		var chunk [1024]byte
+		const chunkLen = len(chunk) / 4 * 3
+		var written int
+		for src := b.data; len(src) > 0; {
+			l := len(src)
+			if l > chunkLen {
+				l = chunkLen
+			}
+			n := enc.EncodedLen(l)
+			enc.Encode(chunk[:n], src[:l])
+			sb.Write(chunk[:n])
+			written += n
+			src = src[l:]
+		}
+		return written, nil
+	default:
+		return sb.WriteString(enc.EncodeToString(b.data))
+	}
+}
+
+// WriteWrappedTo writes the base64 encoded form of the buffer to `w`,
+// inserting a newline after every `width` characters, as is done in PEM
+// (64 characters) and MIME (76 characters) payloads. The last line is
+// also terminated by a newline. Nothing is written if the buffer is empty.
+//
+// The data is encoded using the B64Encoder object associated with this
+// object (or the global one, if not specified), in the same way as
+// `WriteTo`. Use `StripWhitespace` to decode the output.
+func (b *Buffer) WriteWrappedTo(w io.Writer, width int) error {
+	if width <= 0 {
+		return fmt.Errorf(`failed to write wrapped byteslice.Buffer: invalid width %d`, width)
+	}
+
+	lw := &lineWrapper{w: w, width: width}
+	if _, err := b.WriteTo(lw); err != nil {
+		return fmt.Errorf(`failed to write wrapped byteslice.Buffer: %w`, err)
+	}
+	if lw.col > 0 {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return fmt.Errorf(`failed to write wrapped byteslice.Buffer: %w`, err)
+		}
+	}
+	return nil
+}
+
+// lineWrapper is an `io.Writer` that inserts a newline after every
+// `width` bytes written to it
+type lineWrapper struct {
+	w     io.Writer
+	width int
+	col   int
+}
+
+func (lw *lineWrapper) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		n := lw.width - lw.col
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := lw.w.Write(p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		lw.col += n
+		p = p[n:]
+
+		if lw.col == lw.width {
+			if _, err := io.WriteString(lw.w, "\n"); err != nil {
+				return written, err
+			}
+			lw.col = 0
+		}
+	}
+	return written, nil
+}
+
+// DecodeStream reads base64 encoded data from `r` until EOF, decodes it
+// using `dec`, and writes the result to `w`. It returns the number of
+// decoded bytes written to `w`.
+//
+// The data is decoded while it is being read, so that arbitrarily large
+// inputs can be processed using a small, constant amount of memory.
+// Whitespace in the input, such as the line breaks in PEM or MIME style
+// payloads, is ignored.
+func DecodeStream(r io.Reader, w io.Writer, dec *base64.Encoding) (int64, error) {
+	n, err := io.Copy(w, base64.NewDecoder(dec, &whitespaceFilter{r: r}))
+	if err != nil {
+		var cerr base64.CorruptInputError
+		if errors.As(err, &cerr) {
+			err = withKind(ErrInvalidEncoding, err)
+		}
+		return n, fmt.Errorf(`failed to decode stream: %w`, err)
+	}
+	return n, nil
+}
+
+// whitespaceFilter is an `io.Reader` that removes whitespace from the
+// data read from `r`
+type whitespaceFilter struct {
+	r io.Reader
+}
+
+func (f *whitespaceFilter) Read(p []byte) (int, error) {
+	for {
+		n, err := f.r.Read(p)
+		j := 0
+		for _, c := range p[:n] {
+			if !isWhitespace(rune(c)) {
+				p[j] = c
+				j++
+			}
+		}
+		// avoid returning (0, nil) when the chunk was all whitespace
+		if j > 0 || err != nil || n == 0 {
+			return j, err
+		}
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,223 @@
+package byteslice_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkedReader returns at most `size` bytes per call to Read
+type chunkedReader struct {
+	r    io.Reader
+	size int
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if len(p) > r.size {
+		p = p[:r.size]
+	}
+	return r.r.Read(p)
+}
+
+func TestDecodeFrom(t *testing.T) {
+	payload := make([]byte, 3<<20)
+	rand.New(rand.NewSource(0)).Read(payload)
+
+	t.Run("base64.Encoding", func(t *testing.T) {
+		encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+		var v byteslice.Buffer
+		v.SetB64Decoder(base64.RawURLEncoding)
+		n, err := v.DecodeFrom(&chunkedReader{r: strings.NewReader(encoded), size: 13})
+		require.NoError(t, err, `DecodeFrom should succeed`)
+		require.Equal(t, int64(len(encoded)), n)
+		require.Equal(t, payload, v.Bytes())
+	})
+	t.Run("heuristic decoder", func(t *testing.T) {
+		encoded := base64.StdEncoding.EncodeToString(payload)
+
+		var v byteslice.Buffer
+		n, err := v.DecodeFrom(&chunkedReader{r: strings.NewReader(encoded), size: 13})
+		require.NoError(t, err, `DecodeFrom should succeed`)
+		require.Equal(t, int64(len(encoded)), n)
+		require.Equal(t, payload, v.Bytes())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var v byteslice.Buffer
+		v.SetB64Decoder(base64.StdEncoding)
+		_, err := v.DecodeFrom(strings.NewReader(`!!!!`))
+		require.Error(t, err, `DecodeFrom should fail`)
+	})
+}
+
+func TestWriteTo(t *testing.T) {
+	payload := make([]byte, 1<<20+1)
+	rand.New(rand.NewSource(0)).Read(payload)
+
+	testcases := []struct {
+		Name    string
+		Encoder byteslice.B64Encoder
+	}{
+		{Name: "Std", Encoder: base64.StdEncoding},
+		{Name: "RawURL", Encoder: base64.RawURLEncoding},
+		{Name: "Hex", Encoder: byteslice.HexEncoder},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(payload)
+			v.SetB64Encoder(tc.Encoder)
+
+			var dst bytes.Buffer
+			n, err := v.WriteTo(&dst)
+			require.NoError(t, err, `WriteTo should succeed`)
+			require.Equal(t, int64(dst.Len()), n)
+			require.Equal(t, tc.Encoder.EncodeToString(payload), dst.String())
+		})
+	}
+	t.Run("nil", func(t *testing.T) {
+		var v *byteslice.Buffer
+		var dst bytes.Buffer
+		n, err := v.WriteTo(&dst)
+		require.NoError(t, err, `WriteTo should succeed`)
+		require.Equal(t, int64(0), n)
+		require.Equal(t, ``, dst.String())
+	})
+}
+
+func TestWriteEncodedTo(t *testing.T) {
+	payload := make([]byte, 1<<16+1)
+	rand.New(rand.NewSource(0)).Read(payload)
+
+	testcases := []struct {
+		Name    string
+		Encoder byteslice.B64Encoder
+	}{
+		{Name: "Std", Encoder: base64.StdEncoding},
+		{Name: "RawURL", Encoder: base64.RawURLEncoding},
+		{Name: "Hex", Encoder: byteslice.HexEncoder},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			v := byteslice.New(payload)
+			v.SetB64Encoder(tc.Encoder)
+
+			var sb strings.Builder
+			sb.WriteString(`prefix:`)
+			n, err := v.WriteEncodedTo(&sb)
+			require.NoError(t, err, `WriteEncodedTo should succeed`)
+			require.Equal(t, sb.Len()-len(`prefix:`), n)
+			require.Equal(t, `prefix:`+tc.Encoder.EncodeToString(payload), sb.String())
+		})
+	}
+	t.Run("empty", func(t *testing.T) {
+		var v byteslice.Buffer
+		var sb strings.Builder
+		n, err := v.WriteEncodedTo(&sb)
+		require.NoError(t, err, `WriteEncodedTo should succeed`)
+		require.Equal(t, 0, n)
+		require.Equal(t, ``, sb.String())
+	})
+}
+
+func BenchmarkWriteEncodedTo(b *testing.B) {
+	payload := make([]byte, 4096)
+	rand.New(rand.NewSource(0)).Read(payload)
+	v := byteslice.New(payload)
+
+	b.Run("WriteString(EncodeToString())", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var sb strings.Builder
+			sb.WriteString(v.EncodeToString())
+		}
+	})
+	b.Run("WriteEncodedTo", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var sb strings.Builder
+			_, _ = v.WriteEncodedTo(&sb)
+		}
+	})
+}
+
+func TestWriteWrappedTo(t *testing.T) {
+	payload := make([]byte, 1000)
+	rand.New(rand.NewSource(0)).Read(payload)
+
+	for _, width := range []int{64, 76} {
+		width := width
+		t.Run(strconv.Itoa(width), func(t *testing.T) {
+			v := byteslice.New(payload)
+
+			var dst bytes.Buffer
+			require.NoError(t, v.WriteWrappedTo(&dst, width), `WriteWrappedTo should succeed`)
+
+			out := dst.String()
+			require.True(t, strings.HasSuffix(out, "\n"), `output should end with a newline`)
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			for i, line := range lines {
+				if i < len(lines)-1 {
+					require.Len(t, line, width, `line %d should be exactly %d characters`, i, width)
+				} else {
+					require.LessOrEqual(t, len(line), width, `last line should be at most %d characters`, width)
+				}
+			}
+			require.Equal(t, base64.StdEncoding.EncodeToString(payload), strings.Join(lines, ``))
+
+			var rt byteslice.Buffer
+			rt.SetB64Decoder(byteslice.StripWhitespace(base64.StdEncoding))
+			require.NoError(t, rt.DecodeString(out), `DecodeString should succeed`)
+			require.Equal(t, payload, rt.Bytes())
+		})
+	}
+	t.Run("exact multiple", func(t *testing.T) {
+		var dst bytes.Buffer
+		require.NoError(t, byteslice.New([]byte(`Alice!`)).WriteWrappedTo(&dst, 4), `WriteWrappedTo should succeed`)
+		require.Equal(t, "QWxp\nY2Uh\n", dst.String())
+	})
+	t.Run("empty", func(t *testing.T) {
+		var dst bytes.Buffer
+		require.NoError(t, new(byteslice.Buffer).WriteWrappedTo(&dst, 64), `WriteWrappedTo should succeed`)
+		require.Equal(t, ``, dst.String())
+	})
+	t.Run("invalid width", func(t *testing.T) {
+		require.Error(t, byteslice.New([]byte(`Alice`)).WriteWrappedTo(io.Discard, 0), `WriteWrappedTo should fail`)
+	})
+}
+
+func TestDecodeStream(t *testing.T) {
+	payload := make([]byte, 4<<20+1)
+	rand.New(rand.NewSource(0)).Read(payload)
+
+	t.Run("line-wrapped", func(t *testing.T) {
+		var src bytes.Buffer
+		require.NoError(t, byteslice.New(payload).WriteWrappedTo(&src, 76), `WriteWrappedTo should succeed`)
+
+		var dst bytes.Buffer
+		n, err := byteslice.DecodeStream(&chunkedReader{r: &src, size: 1000}, &dst, base64.StdEncoding)
+		require.NoError(t, err, `DecodeStream should succeed`)
+		require.Equal(t, int64(len(payload)), n)
+		require.Equal(t, payload, dst.Bytes())
+	})
+	t.Run("spaces and tabs", func(t *testing.T) {
+		var dst bytes.Buffer
+		_, err := byteslice.DecodeStream(strings.NewReader("  -__-\t\r\n QWxp Y2U\n"), &dst, base64.RawURLEncoding)
+		require.NoError(t, err, `DecodeStream should succeed`)
+		require.Equal(t, []byte{0xfb, 0xff, 0xfe, 'A', 'l', 'i', 'c', 'e'}, dst.Bytes())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := byteslice.DecodeStream(strings.NewReader(`QWxp!!!!`), io.Discard, base64.StdEncoding)
+		require.Error(t, err, `DecodeStream should fail`)
+		require.True(t, errors.Is(err, byteslice.ErrInvalidEncoding), `error should match ErrInvalidEncoding`)
+	})
+}
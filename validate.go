@@ -0,0 +1,20 @@
+package byteslice
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// ValidateUTF8 is a validator that can be passed to
+// `Buffer.SetDecodeValidator()`. It returns an error if `data`
+// is not a valid UTF-8 encoded string.
+func ValidateUTF8(data []byte) error {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return fmt.Errorf(`invalid UTF-8 sequence at position %d`, i)
+		}
+		i += size
+	}
+	return nil
+}
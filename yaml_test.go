@@ -0,0 +1,41 @@
+package byteslice_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/lestrrat-go/byteslice"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAML(t *testing.T) {
+	type foo struct {
+		Bar byteslice.Buffer `yaml:"bar"`
+	}
+
+	testcases := []struct {
+		Name     string
+		Encoder  byteslice.B64Encoder
+		Expected string
+	}{
+		{Name: "padded", Encoder: base64.StdEncoding, Expected: "bar: QWxpY2U=\n"},
+		{Name: "unpadded", Encoder: base64.RawURLEncoding, Expected: "bar: QWxpY2U\n"},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			var src foo
+			src.Bar.SetBytes([]byte(`Alice`))
+			src.Bar.SetB64Encoder(tc.Encoder)
+
+			buf, err := yaml.Marshal(src)
+			require.NoError(t, err, `yaml.Marshal should succeed`)
+			require.Equal(t, tc.Expected, string(buf))
+
+			var dst foo
+			require.NoError(t, yaml.Unmarshal(buf, &dst), `yaml.Unmarshal should succeed`)
+			require.Equal(t, []byte(`Alice`), dst.Bar.Bytes())
+		})
+	}
+}